@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/blizz_oath"
@@ -17,6 +18,8 @@ import (
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cache_provider"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/environment_variables"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/util"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/auction_history"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/blizzard_api_helpers"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/wow_crafting_profits"
@@ -36,6 +39,31 @@ func main() {
 	fJsonData := flag.String("json_data", "", "JSON configuration data")
 	fUseJsonFlag := flag.Bool("json", false, "Use JSON to configure region, realm, and professions")
 	fAllProfessionsFlag := flag.Bool("allprof", true, "Use all professions and ignore profession flag")
+	fListingDuration := flag.Uint("listing_duration", 24, "Auction listing duration in hours, used to estimate deposits")
+	fScanFlag := flag.Bool("scan", false, "Scan every recipe of the selected professions and rank the most profitable crafts")
+	fScanLimit := flag.Uint("scan_limit", 25, "How many of the most profitable crafts to report when scanning")
+	fArbitrageFlag := flag.Bool("arbitrage", false, "Compare buying reagents and selling the item across every scanned realm of the region, needs auction history")
+	fOrderFlag := flag.Bool("order", false, "Evaluate crafting the item for a crafting order, using order_reagents and commission")
+	fOrderReagents := flag.String("order_reagents", "", `Reagents the crafting order customer provides as JSON keyed by item id, e.g. {"2001":6}`)
+	fCommission := flag.Float64("commission", 0, "Commission in copper the crafting order pays")
+	fPlan := flag.String("plan", "", `Plan crafting several items at once sharing one inventory, as JSON of how many of each item id or name to make, e.g. {"171276":20,"171270":40}`)
+	fOfflineData := flag.String("offline_data", "", "Run without network access or credentials, using item, recipe and realm data saved with -record_data")
+	fRecordData := flag.String("record_data", "", "Save all item, recipe, realm and auction data fetched during the run to this file for later offline use")
+	fAuctions := flag.String("auctions", "", "Use an auction house snapshot JSON file instead of the live auction house")
+	fPricing := flag.String("pricing", "orderbook", "Reagent pricing strategy: min, average, median, percentile[:N], orderbook[:units] or history[:days]")
+	fReagentQuality := flag.Uint("reagent_quality", 0, "Crafting quality tier of reagents to craft with, 0 uses the cheapest tier on the auction house")
+	fCrafterStats := flag.String("crafter_stats", "", `Crafter stats for each profession as JSON, e.g. {"Alchemy":{"multicraft":20,"resourcefulness":15}}`)
+	fSlotReagents := flag.String("slot_reagents", "", `Reagent to use in each optional or finishing slot as JSON keyed by slot type id, e.g. {"92":190872}`)
+	fMaxDepth := flag.Uint("max_depth", 0, "Levels of reagents to craft rather than buy, 0 for no limit")
+	fRawMaterials := flag.String("raw_materials", "", "Comma separated item ids of reagents to always buy, never craft")
+	fMinCraftValue := flag.Float64("min_craft_value", 0, "Buy reagents worth less than this many copper on the auction house instead of crafting them")
+	fVendorPrices := flag.String("vendor_prices", "", `Vendor price per unit in copper for items as JSON keyed by item id, overriding the static vendor list, e.g. {"3371":100}`)
+	fGuessVendor := flag.Bool("guess_vendor_prices", false, "Guess whether vendors sell reagents missing from both vendor price lists from their English descriptions, otherwise they are not vendor sold")
+	fTargetPrice := flag.Float64("target_price", 0, "What if the item sold for this many copper each, reports break-even reagent prices and how many crafts stay profitable")
+	fUndercut := flag.Float64("undercut", 0, "What if the item sold this percent below the current lowest listing, ignored when target_price is set")
+	fBudget := flag.Float64("budget", 0, "Copper to spend, reports how many of the item it can craft and with which rank and intermediates")
+	fExplain := flag.Bool("explain", false, "Record every pricing, recipe and rank decision of the run to explain_output.json")
+	fExport := flag.String("export", "", "Also save the shopping list to shopping_list_export as an in-game import string: tsm_group, tsm_shopping, auctionator or cpc")
 	flag.Parse()
 
 	var character_config_json globalTypes.AddonData
@@ -65,6 +93,49 @@ func main() {
 
 	config := globalTypes.NewRunConfig(&character_config_json, item, *fCount)
 	config.UseAllProfessions = *fAllProfessionsFlag
+	config.Listing_duration = *fListingDuration
+	config.Pricing_strategy = *fPricing
+	config.Reagent_quality = *fReagentQuality
+	if *fCrafterStats != "" {
+		if err := json.Unmarshal([]byte(*fCrafterStats), &config.Crafter_stats); err != nil {
+			logger.Fatalf("Crafter stats cannot be parsed: %v", err)
+		}
+	}
+	if *fSlotReagents != "" {
+		if err := json.Unmarshal([]byte(*fSlotReagents), &config.Slot_reagents); err != nil {
+			logger.Fatalf("Slot reagents cannot be parsed: %v", err)
+		}
+	}
+
+	config.Max_depth = *fMaxDepth
+	if *fRawMaterials != "" {
+		config.Raw_materials = util.ParseStringArrayToUint(strings.Fields(strings.ReplaceAll(*fRawMaterials, ",", " ")))
+	}
+	config.Min_craft_value = *fMinCraftValue
+	config.Explain = *fExplain
+	config.Export_format = *fExport
+	if *fVendorPrices != "" {
+		if err := json.Unmarshal([]byte(*fVendorPrices), &config.Vendor_prices); err != nil {
+			logger.Fatalf("Vendor prices cannot be parsed: %v", err)
+		}
+	}
+	config.Guess_vendor_prices = *fGuessVendor
+	config.Target_price = *fTargetPrice
+	config.Undercut_percent = *fUndercut
+	config.Budget = *fBudget
+	if *fOrderReagents != "" {
+		if err := json.Unmarshal([]byte(*fOrderReagents), &config.Order_reagents); err != nil {
+			logger.Fatalf("Order reagents cannot be parsed: %v", err)
+		}
+	}
+	config.Commission = *fCommission
+	if *fPlan != "" {
+		var plan_items map[string]uint
+		if err := json.Unmarshal([]byte(*fPlan), &plan_items); err != nil {
+			logger.Fatalf("Plan cannot be parsed: %v", err)
+		}
+		config.Plan_items = globalTypes.NewPlanItems(plan_items)
+	}
 
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -79,16 +150,54 @@ func main() {
 		cancel()
 	}()
 
-	tokenServer := blizz_oath.NewTokenServer(environment_variables.CLIENT_ID, environment_variables.CLIENT_SECRET, logger)
-	cache := cache_provider.NewCacheProvider(ctx, environment_variables.REDIS_URL)
-	api := blizzard_api_call.NewBlizzardApiProvider(tokenServer, logger)
-	helper := blizzard_api_helpers.NewBlizzardApiHelper(cache, logger, api)
+	var helper *blizzard_api_helpers.BlizzardApiHelper
+	if *fOfflineData != "" {
+		offline_helper, err := blizzard_api_helpers.NewOfflineBlizzardApiHelper(*fOfflineData, logger)
+		if err != nil {
+			logger.Errorf("Could not load offline data: %v", err)
+			return
+		}
+		helper = offline_helper
+	} else {
+		tokenServer := blizz_oath.NewTokenServer(environment_variables.CLIENT_ID, environment_variables.CLIENT_SECRET, logger)
+		var cache *cache_provider.CacheProvider
+		if *fRecordData != "" {
+			cache = cache_provider.NewMemoryCacheProvider()
+		} else {
+			cache = cache_provider.NewCacheProvider(ctx, environment_variables.REDIS_URL)
+		}
+		api := blizzard_api_call.NewBlizzardApiProvider(tokenServer, logger)
+		helper = blizzard_api_helpers.NewBlizzardApiHelper(cache, logger, api)
+	}
 	cpc := wow_crafting_profits.WoWCpCRunner{
 		Helper: helper,
 		Logger: logger,
 	}
+	if *fAuctions != "" {
+		auctions, err := wow_crafting_profits.LoadAuctionSnapshot(*fAuctions)
+		if err != nil {
+			logger.Errorf("Could not load auction snapshot: %v", err)
+			return
+		}
+		cpc.Auctions = auctions
+	}
+	if (*fArbitrageFlag || strings.HasPrefix(*fPricing, "history")) && environment_variables.DATABASE_CONNECTION_STRING != "" {
+		cpc.History = auction_history.NewAuctionHistoryServer(ctx, environment_variables.DATABASE_CONNECTION_STRING, helper, logger)
+		defer cpc.History.Shutdown()
+	}
 
-	runErr := cpc.CliRun(ctx, config)
+	var runErr error
+	if *fScanFlag {
+		runErr = cpc.CliScan(ctx, config, *fScanLimit)
+	} else if *fArbitrageFlag {
+		runErr = cpc.CliArbitrage(ctx, config)
+	} else if *fOrderFlag {
+		runErr = cpc.CliCraftingOrder(ctx, config)
+	} else if *fPlan != "" {
+		runErr = cpc.CliPlan(ctx, config)
+	} else {
+		runErr = cpc.CliRun(ctx, config)
+	}
 	if runErr != nil {
 		if errors.Is(runErr, context.Canceled) {
 			logger.Info("Operation cancelled by user.")
@@ -96,4 +205,10 @@ func main() {
 			logger.Errorf("Run error: %v", runErr)
 		}
 	}
+
+	if *fRecordData != "" && *fOfflineData == "" {
+		if err := helper.SaveLocalData(*fRecordData); err != nil {
+			logger.Errorf("Could not save offline data: %v", err)
+		}
+	}
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cache_provider"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/environment_variables"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/auction_history"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/blizzard_api_helpers"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/wow_crafting_profits"
@@ -44,6 +45,10 @@ func main() {
 		Helper: helper,
 		Logger: logger,
 	}
+	if !environment_variables.DISABLE_AUCTION_HISTORY && environment_variables.DATABASE_CONNECTION_STRING != "" {
+		cpc.History = auction_history.NewAuctionHistoryServer(ctx, environment_variables.DATABASE_CONNECTION_STRING, helper, logger)
+		defer cpc.History.Shutdown()
+	}
 
 	closeRequested := make(chan os.Signal, 1)
 	signal.Notify(closeRequested, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -98,17 +103,60 @@ func main() {
 
 				logger.Infof(`Got new job with id %s -> %v`, run_id, run_config)
 				config := globalTypes.NewRunConfig(&run_config.AddonData, run_config.Item, run_config.Count)
+				config.Pricing_strategy = run_config.Pricing_strategy
+				config.Reagent_quality = run_config.Reagent_quality
+				config.Crafter_stats = run_config.Crafter_stats
+				config.Slot_reagents = run_config.Slot_reagents
+				config.Max_depth = run_config.Max_depth
+				config.Raw_materials = run_config.Raw_materials
+				config.Min_craft_value = run_config.Min_craft_value
+				config.Explain = run_config.Explain
+				config.Vendor_prices = run_config.Vendor_prices
+				config.Guess_vendor_prices = run_config.Guess_vendor
+				config.Target_price = run_config.Target_price
+				config.Undercut_percent = run_config.Undercut_percent
+				config.Order_reagents = run_config.Order_reagents
+				config.Commission = run_config.Commission
+				config.Plan_items = run_config.Plan_items
+				config.Budget = run_config.Budget
 				job_key := fmt.Sprintf(globalTypes.CPC_JOB_RETURN_FORMAT_STRING, run_id)
+				progress_key := fmt.Sprintf(globalTypes.CPC_JOB_PROGRESS_KEY_FORMAT, run_id)
+
+				// Publish progress for the check endpoint, at most once a second so redis is not flooded
+				job_cpc := cpc
+				var last_published time.Time
+				job_cpc.Progress = func(progress globalTypes.RunProgress) {
+					if time.Since(last_published) < time.Second {
+						return
+					}
+					last_published = time.Now()
+					if progress_json, err := json.Marshal(progress); err == nil {
+						redisClient.SetEX(ctx, progress_key, progress_json, time.Hour)
+					}
+				}
+				defer redisClient.Del(ctx, progress_key)
 
 				// Use worker context for the run
-				data, err := cpc.RunWithJSONConfig(ctx, config)
+				var data any
+				switch run_config.Mode {
+				case globalTypes.RUN_MODE_SCAN:
+					data, err = job_cpc.ScanWithJSONConfig(ctx, config, run_config.Limit)
+				case globalTypes.RUN_MODE_ARBITRAGE:
+					data, err = job_cpc.ArbitrageWithJSONConfig(ctx, config)
+				case globalTypes.RUN_MODE_ORDER:
+					data, err = job_cpc.CraftingOrderWithJSONConfig(ctx, config)
+				case globalTypes.RUN_MODE_PLAN:
+					data, err = job_cpc.PlanWithJSONConfig(ctx, config)
+				default:
+					data, err = job_cpc.RunWithJSONConfig(ctx, config)
+				}
 				if err != nil {
 					logger.Infof("Job %s failed: %v", run_id, err)
 					redisClient.SetEX(ctx, job_key, job_error_return, time.Hour)
 					return
 				}
 
-				job_save, err := json.Marshal(&data)
+				job_save, err := json.Marshal(data)
 				if err != nil {
 					logger.Error("Issue marshaling job results: ", err)
 					return
@@ -3,10 +3,12 @@ package blizzard_api_call
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
@@ -35,8 +37,28 @@ const (
 	CHINESE_SIMPLIFIED  string = "zh_CN" // Chinese (Simplified)
 )
 
+// Returned for any request made without an api provider, such as when running from local data
+var ErrOffline = errors.New("blizzard api is not available offline")
+
+type callCounterKey struct{}
+
+// Count every request sent to the Blizzard API with the returned context, retries included, in counter
+func WithCallCounter(ctx context.Context, counter *atomic.Uint64) context.Context {
+	return context.WithValue(ctx, callCounterKey{}, counter)
+}
+
+func countCall(ctx context.Context) {
+	if counter, ok := ctx.Value(callCounterKey{}).(*atomic.Uint64); ok {
+		counter.Add(1)
+	}
+}
+
 // getAndFill retrieves data from Blizzard API and unmarshals it into the target struct.
 func getAndFill[T BlizzardApi.BlizzardApiReponse](ctx context.Context, api *BlizzardApiProvider, uri string, region globalTypes.RegionCode, data map[string]string, namespace string, target *T) error {
+	if api == nil {
+		return fmt.Errorf("%w: %s", ErrOffline, uri)
+	}
+
 	token, tokenErr := api.TokenServer.GetAuthorizationToken(ctx, string(region))
 	if tokenErr != nil {
 		return tokenErr
@@ -69,6 +91,7 @@ func getAndFill[T BlizzardApi.BlizzardApiReponse](ctx context.Context, api *Bliz
 			return fmt.Errorf("rate limiter wait error: %w", err)
 		}
 
+		countCall(ctx)
 		res, lastErr = api.HttpClient.Do(req)
 		if lastErr != nil {
 			api.Logger.Debugf("Attempt %d: Failure fetching uri %s: %v. Retrying...", attempt+1, uri, lastErr)
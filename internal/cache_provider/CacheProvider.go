@@ -2,6 +2,8 @@ package cache_provider
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -9,6 +11,8 @@ import (
 type CacheProvider struct {
 	redisClient *redis.Client
 	ctx         context.Context
+	memory      map[string]map[string]json.RawMessage // namespace -> key -> value, used when there is no redis
+	memoryMutex sync.RWMutex
 }
 
 func NewCacheProvider(ctx context.Context, uri string) *CacheProvider {
@@ -21,3 +25,10 @@ func NewCacheProvider(ctx context.Context, uri string) *CacheProvider {
 		ctx:         ctx,
 	}
 }
+
+// Create a cache held entirely in memory, it never expires and can be saved to or loaded from a local file
+func NewMemoryCacheProvider() *CacheProvider {
+	return &CacheProvider{
+		memory: make(map[string]map[string]json.RawMessage),
+	}
+}
@@ -2,6 +2,7 @@ package cache_provider
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -12,6 +13,13 @@ func getRedisKey(namespace string, key string) string {
 
 // Fetch an object from the cache, or fail
 func CacheGet[T any](cache *CacheProvider, namespace string, key string, target *T) error {
+	if cache.memory != nil {
+		data, found := cache.memoryGet(namespace, key)
+		if !found {
+			return fmt.Errorf("%s not found in %s", key, namespace)
+		}
+		return json.Unmarshal(data, &target)
+	}
 	data, getErr := cache.redisClient.Get(cache.ctx, getRedisKey(namespace, key)).Bytes()
 	if getErr != nil {
 		return getErr
@@ -29,6 +37,10 @@ func CacheSet[T any](cache *CacheProvider, namespace string, key string, data T,
 	if err != nil {
 		return err
 	}
+	if cache.memory != nil {
+		cache.memorySet(namespace, key, json_data)
+		return nil
+	}
 	//bufW.Flush()
 	setErr := cache.redisClient.Set(cache.ctx, getRedisKey(namespace, key), json_data, expiration_period).Err()
 	if setErr != nil {
@@ -39,6 +51,10 @@ func CacheSet[T any](cache *CacheProvider, namespace string, key string, data T,
 
 // Check if a given key exists in a given namespace
 func CacheCheck(cache *CacheProvider, namespace string, key string) (bool, error) {
+	if cache.memory != nil {
+		_, found := cache.memoryGet(namespace, key)
+		return found, nil
+	}
 	fnd, err := cache.redisClient.Exists(cache.ctx, getRedisKey(namespace, key)).Result()
 	if err != nil {
 		return false, err
@@ -0,0 +1,67 @@
+package cache_provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNotMemoryCache = errors.New("cache is not held in memory")
+
+func (cache *CacheProvider) memoryGet(namespace string, key string) (json.RawMessage, bool) {
+	cache.memoryMutex.RLock()
+	defer cache.memoryMutex.RUnlock()
+	data, found := cache.memory[namespace][key]
+	return data, found
+}
+
+func (cache *CacheProvider) memorySet(namespace string, key string, data json.RawMessage) {
+	cache.memoryMutex.Lock()
+	defer cache.memoryMutex.Unlock()
+	if _, present := cache.memory[namespace]; !present {
+		cache.memory[namespace] = make(map[string]json.RawMessage)
+	}
+	cache.memory[namespace][key] = data
+}
+
+// Load a dump written by SaveDump into a memory cache, merging it with anything already cached
+func (cache *CacheProvider) LoadDump(fn string) error {
+	if cache.memory == nil {
+		return ErrNotMemoryCache
+	}
+	file, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var dump map[string]map[string]json.RawMessage
+	if err := json.NewDecoder(file).Decode(&dump); err != nil {
+		return fmt.Errorf("error parsing cache dump %s: %w", fn, err)
+	}
+	for namespace, values := range dump {
+		for key, data := range values {
+			cache.memorySet(namespace, key, data)
+		}
+	}
+	return nil
+}
+
+// Save everything in a memory cache to a local file, grouped by namespace
+func (cache *CacheProvider) SaveDump(fn string) error {
+	if cache.memory == nil {
+		return ErrNotMemoryCache
+	}
+	file, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	cache.memoryMutex.RLock()
+	defer cache.memoryMutex.RUnlock()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cache.memory)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/shopping_list_export"
 )
 
 type jsonOutputBodyQueueData struct {
@@ -18,6 +19,57 @@ type jsonOutputBodyQueueData struct {
 	Professions       []string `json:"professions,omitempty"`
 	Server            string   `json:"server,omitempty"`
 	Region            string   `json:"region,omitempty"`
+	Limit             uint     `json:"limit,omitempty"`
+	PricingStrategy   string   `json:"pricing_strategy,omitempty"`
+	ReagentQuality    uint     `json:"reagent_quality,omitempty"`
+
+	CrafterStats map[globalTypes.CharacterProfession]globalTypes.CrafterStats `json:"crafter_stats,omitempty"`
+	SlotReagents map[uint]globalTypes.ItemID                                  `json:"slot_reagents,omitempty"`
+
+	MaxDepth      uint                           `json:"max_depth,omitempty"`
+	RawMaterials  []globalTypes.ItemID           `json:"raw_materials,omitempty"`
+	MinCraftValue float64                        `json:"min_craft_value,omitempty"`
+	Explain       bool                           `json:"explain,omitempty"`
+	VendorPrices  map[globalTypes.ItemID]float64 `json:"vendor_prices,omitempty"`
+	GuessVendor   bool                           `json:"guess_vendor_prices,omitempty"`
+
+	TargetPrice     float64 `json:"target_price,omitempty"`
+	UndercutPercent float64 `json:"undercut_percent,omitempty"`
+	Budget          float64 `json:"budget,omitempty"`
+
+	OrderReagents map[globalTypes.ItemID]uint `json:"order_reagents,omitempty"`
+	Commission    float64                     `json:"commission,omitempty"`
+
+	PlanItems map[string]uint `json:"plan_items,omitempty"`
+}
+
+// The run options every job type takes from the request, each type adds its own
+func (data jsonOutputBodyQueueData) sharedJobConfig() globalTypes.RunJobConfig {
+	return globalTypes.RunJobConfig{
+		UseAllProfessions: data.UseAllProfessions,
+		Pricing_strategy:  data.PricingStrategy,
+		Reagent_quality:   data.ReagentQuality,
+		Crafter_stats:     data.CrafterStats,
+		Slot_reagents:     data.SlotReagents,
+		Max_depth:         data.MaxDepth,
+		Raw_materials:     data.RawMaterials,
+		Min_craft_value:   data.MinCraftValue,
+		Explain:           data.Explain,
+		Vendor_prices:     data.VendorPrices,
+		Guess_vendor:      data.GuessVendor,
+	}
+}
+
+// The addon data with the professions and realm from the request, when it has them
+func (data jsonOutputBodyQueueData) requestAddonData(addon_data globalTypes.AddonData) globalTypes.AddonData {
+	if len(data.Professions) > 0 {
+		addon_data.Professions = data.Professions
+	}
+	if data.Server != "" {
+		addon_data.Realm.Realm_name = data.Server
+		addon_data.Realm.Region_name = data.Region
+	}
+	return addon_data
 }
 
 // Queue up a CPC run
@@ -40,60 +92,72 @@ func (routes *CPCRoutes) JsonOutputQueue(w http.ResponseWriter, r *http.Request)
 	var adData globalTypes.AddonData
 	_ = json.Unmarshal([]byte(data.AddonData), &adData)
 
+	job_config := data.sharedJobConfig()
 	switch data.Type {
 	case "custom":
 		routes.Logger.Debugf(`Custom search for item: %s, server: %s, region: %s`, data.ItemId, data.Server, data.Region)
-		runJob := globalTypes.RunJob{
-			JobId: jobUUID,
-			JobConfig: struct {
-				Item              globalTypes.ItemSoftIdentity
-				Count             uint
-				UseAllProfessions bool
-				AddonData         globalTypes.AddonData
+		job_config.Item = globalTypes.NewItemFromString(data.ItemId)
+		job_config.Count = data.Count
+		job_config.Target_price = data.TargetPrice
+		job_config.Undercut_percent = data.UndercutPercent
+		job_config.Budget = data.Budget
+		job_config.AddonData = globalTypes.AddonData{
+			Inventory:   adData.Inventory,
+			Professions: data.Professions,
+			Realm: struct {
+				Region_id   uint   "json:\"region_id,omitempty\""
+				Region_name string "json:\"region_name,omitempty\""
+				Realm_id    uint   "json:\"realm_id,omitempty\""
+				Realm_name  string "json:\"realm_name,omitempty\""
 			}{
-				Item:              globalTypes.NewItemFromString(data.ItemId),
-				Count:             data.Count,
-				UseAllProfessions: data.UseAllProfessions,
-				AddonData: globalTypes.AddonData{
-					Inventory:   adData.Inventory,
-					Professions: data.Professions,
-					Realm: struct {
-						Region_id   uint   "json:\"region_id,omitempty\""
-						Region_name string "json:\"region_name,omitempty\""
-						Realm_id    uint   "json:\"realm_id,omitempty\""
-						Realm_name  string "json:\"realm_name,omitempty\""
-					}{
-						Realm_name:  data.Server,
-						Region_name: data.Region,
-					},
-				},
+				Realm_name:  data.Server,
+				Region_name: data.Region,
 			},
 		}
-		rjs, _ := json.Marshal(runJob)
-		routes.redisClient.LPush(r.Context(), globalTypes.CPC_JOB_QUEUE_NAME, rjs)
 	case "json":
 		routes.Logger.Debug("json search")
-		runJob := globalTypes.RunJob{
-			JobId: jobUUID,
-			JobConfig: struct {
-				Item              globalTypes.ItemSoftIdentity
-				Count             uint
-				UseAllProfessions bool
-				AddonData         globalTypes.AddonData
-			}{
-				Item:              globalTypes.NewItemFromString(data.ItemId),
-				Count:             data.Count,
-				UseAllProfessions: false,
-				AddonData:         adData,
-			},
-		}
-		rjs, _ := json.Marshal(runJob)
-		routes.redisClient.LPush(r.Context(), globalTypes.CPC_JOB_QUEUE_NAME, rjs)
+		job_config.Item = globalTypes.NewItemFromString(data.ItemId)
+		job_config.Count = data.Count
+		job_config.UseAllProfessions = false
+		job_config.AddonData = adData
+		job_config.Target_price = data.TargetPrice
+		job_config.Undercut_percent = data.UndercutPercent
+		job_config.Budget = data.Budget
+	case "scan":
+		routes.Logger.Debugf(`Profession scan for: %v, server: %s, region: %s`, data.Professions, data.Server, data.Region)
+		job_config.Mode = globalTypes.RUN_MODE_SCAN
+		job_config.AddonData = data.requestAddonData(adData)
+		job_config.Limit = data.Limit
+	case "arbitrage":
+		routes.Logger.Debugf(`Realm arbitrage for item: %s, region: %s`, data.ItemId, data.Region)
+		job_config.Mode = globalTypes.RUN_MODE_ARBITRAGE
+		job_config.Item = globalTypes.NewItemFromString(data.ItemId)
+		job_config.Count = data.Count
+		job_config.AddonData = data.requestAddonData(adData)
+	case "order":
+		routes.Logger.Debugf(`Crafting order for item: %s, server: %s, region: %s`, data.ItemId, data.Server, data.Region)
+		job_config.Mode = globalTypes.RUN_MODE_ORDER
+		job_config.Item = globalTypes.NewItemFromString(data.ItemId)
+		job_config.Count = data.Count
+		job_config.AddonData = data.requestAddonData(adData)
+		job_config.Order_reagents = data.OrderReagents
+		job_config.Commission = data.Commission
+	case "plan":
+		routes.Logger.Debugf(`Crafting plan for items: %v, server: %s, region: %s`, data.PlanItems, data.Server, data.Region)
+		job_config.Mode = globalTypes.RUN_MODE_PLAN
+		job_config.AddonData = data.requestAddonData(adData)
+		job_config.Plan_items = globalTypes.NewPlanItems(data.PlanItems)
 	default:
-		http.Error(w, "type must be one of 'custom' or 'json'", http.StatusBadRequest)
+		http.Error(w, "type must be one of 'custom', 'json', 'scan', 'arbitrage', 'order' or 'plan'", http.StatusBadRequest)
 		return
 	}
 
+	rjs, _ := json.Marshal(globalTypes.RunJob{
+		JobId:     jobUUID,
+		JobConfig: job_config,
+	})
+	routes.redisClient.LPush(r.Context(), globalTypes.CPC_JOB_QUEUE_NAME, rjs)
+
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	json.NewEncoder(w).Encode(globalTypes.QueuedJobReturn{
 		JobId: jobUUID,
@@ -124,8 +188,55 @@ func (routes *CPCRoutes) JsonOutputCheck(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-	json.NewEncoder(w).Encode(globalTypes.QueuedJobReturn{
+	// Still running, include how far the job has got if the worker has reported any progress
+	job_return := globalTypes.QueuedJobReturn{
 		JobId: data.JobId,
+	}
+	progress_key := fmt.Sprintf(globalTypes.CPC_JOB_PROGRESS_KEY_FORMAT, data.JobId)
+	if progress_json, err := routes.redisClient.Get(r.Context(), progress_key).Bytes(); err == nil {
+		var progress globalTypes.RunProgress
+		if json.Unmarshal(progress_json, &progress) == nil {
+			job_return.Progress = &progress
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(job_return)
+}
+
+type shoppingListExportBody struct {
+	Format        string                     `json:"format,omitempty"`
+	Name          string                     `json:"name,omitempty"`
+	Shopping_list []globalTypes.ShoppingList `json:"shopping_list,omitempty"`
+}
+
+// Export a shopping list from a CPC run as an in-game addon import string
+func (routes *CPCRoutes) ShoppingListExport(w http.ResponseWriter, r *http.Request) {
+
+	if r.Body == nil {
+		http.Error(w, "body required", http.StatusBadRequest)
+		return
+	}
+
+	var data shoppingListExportBody
+	parseErr := json.NewDecoder(r.Body).Decode(&data)
+	if parseErr != nil {
+		http.Error(w, parseErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exported, err := shopping_list_export.ExportShoppingList(data.Format, data.Name, data.Shopping_list)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(struct {
+		Format string `json:"format"`
+		Export string `json:"export"`
+	}{
+		Format: data.Format,
+		Export: exported,
 	})
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/environment_variables"
@@ -19,14 +20,23 @@ const (
 	raidbots_dl_uri                   string = "https://www.raidbots.com/static/data/live/bonuses.json"                                                                                        // Thank you raidbots
 	firesong_df_crafting_source       string = "https://gist.githubusercontent.com/Firesong25/cc294b9360ab37b01d2350cc266f73e5/raw/a50661505f38d93c46757cf9122b3360a52b601b/CraftedItems.json" //https://gist.github.com/Firesong25/cc294b9360ab37b01d2350cc266f73e5
 	firesong_df_crafting_fn           string = "CraftedItems.json"
+	crafting_quality_fn               string = "crafting-quality.json"
+	reagent_slots_fn                  string = "reagent-slots.json"
+	vendor_prices_fn                  string = "vendor-prices.json"
 )
 
+// Guards the lazily loaded sources and file names of every StaticSources, runs read them from many goroutines
+var sources_mutex sync.Mutex
+
 // StaticSources allows for long cachable data to be saved. A future version may use embed
 type StaticSources struct {
 	bonusCache                               *BonusesCache
 	rankMappingCache                         *RankMappingsCache
 	shoppingRecipeExclusionList              *ShoppingRecipeExclusionList
 	firesongDFCrafting                       *FireSongCraftingLinkTable
+	craftingQuality                          *CraftingQualityCache
+	reagentSlots                             *ReagentSlotsCache
+	vendorPrices                             *VendorPricesCache
 	BonusCacheFileName                       string
 	RankMappingsCacheFileName                string
 	ShoppingRecipeExclusionListCacheFileName string
@@ -34,6 +44,9 @@ type StaticSources struct {
 	RaidbotsURI                              string
 	FireSongDfCraftingUri                    string
 	FireSongDFCraftingFileName               string
+	CraftingQualityFileName                  string
+	ReagentSlotsFileName                     string
+	VendorPricesFileName                     string
 }
 
 // A simplified version of the data availble for bonus mappings from raidbots
@@ -48,6 +61,7 @@ type BonusesCache map[string]struct {
 type RankMappingsCache struct {
 	Available_levels []uint
 	Rank_mapping     []uint
+	Recipe_ranks     map[string]uint // Index into Available_levels for specific recipe ids, overriding Rank_mapping
 }
 
 // A list of recipes to exclude from shopping searches
@@ -69,8 +83,34 @@ type FireSongCraftingLinkTable []struct {
 	CraftedQuantity uint
 }
 
+// Crafting quality data, which auction modifiers carry a quality tier and which items are tiers of the same reagent
+type CraftingQualityCache struct {
+	Quality_modifier_types []int    // Auction modifier types whose value is the quality tier
+	Reagent_tiers          [][]uint // Item ids of each reagent, ordered from the lowest quality tier to the highest
+}
+
+// Reagents that fit each modified crafting slot type, keyed by slot type id
+type ReagentSlotsCache map[string]ReagentSlot
+
+// A modified crafting slot type, Required slots must be filled for the recipe to be crafted
+type ReagentSlot struct {
+	Name     string
+	Required bool
+	Quantity uint // Reagents used per craft, 1 when unset
+	Reagents []uint
+}
+
+// Items sold by vendors, keyed by item id
+type VendorPricesCache map[string]VendorPrice
+
+// What a vendor charges for a single unit of an item in copper, a Price of 0 marks an item vendors do not sell
+type VendorPrice struct {
+	Name  string
+	Price float64
+}
+
 type staticSource interface {
-	BonusesCache | RankMappingsCache | ShoppingRecipeExclusionList | FireSongCraftingLinkTable
+	BonusesCache | RankMappingsCache | ShoppingRecipeExclusionList | FireSongCraftingLinkTable | CraftingQualityCache | ReagentSlotsCache | VendorPricesCache
 }
 
 // load a static resource from the filesystem
@@ -121,6 +161,7 @@ func fetchFromUri[T staticSource](uri string, target *T) error {
 	return nil
 }
 
+// Fill in default file names, callers must hold sources_mutex
 func (s *StaticSources) fillNames() {
 	if len(s.BonusCacheFileName) == 0 {
 		s.BonusCacheFileName = bonuses_cache_fn
@@ -143,10 +184,21 @@ func (s *StaticSources) fillNames() {
 	if len(s.FireSongDFCraftingFileName) == 0 {
 		s.FireSongDFCraftingFileName = firesong_df_crafting_fn
 	}
+	if len(s.CraftingQualityFileName) == 0 {
+		s.CraftingQualityFileName = crafting_quality_fn
+	}
+	if len(s.ReagentSlotsFileName) == 0 {
+		s.ReagentSlotsFileName = reagent_slots_fn
+	}
+	if len(s.VendorPricesFileName) == 0 {
+		s.VendorPricesFileName = vendor_prices_fn
+	}
 }
 
 // Fetch the bonus catch, if it cannot be found locally it will be loaded from raidbots
 func (s *StaticSources) GetBonuses() (*BonusesCache, error) {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
 	s.fillNames()
 	if s.bonusCache == nil {
 		bc := BonusesCache{}
@@ -166,6 +218,8 @@ func (s *StaticSources) GetBonuses() (*BonusesCache, error) {
 
 // Fetch the rank mappings, if not available locally it will be empty
 func (s *StaticSources) GetRankMappings() *RankMappingsCache {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
 	s.fillNames()
 	if s.rankMappingCache == nil {
 		rm := RankMappingsCache{}
@@ -182,6 +236,8 @@ func (s *StaticSources) GetRankMappings() *RankMappingsCache {
 
 // Fetch the shopping list exclusion set, if not available locally it will be empty
 func (s *StaticSources) GetShoppingRecipeExclusionList() *ShoppingRecipeExclusionList {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
 	s.fillNames()
 	if s.shoppingRecipeExclusionList == nil {
 		sre := ShoppingRecipeExclusionList{}
@@ -195,10 +251,60 @@ func (s *StaticSources) GetShoppingRecipeExclusionList() *ShoppingRecipeExclusio
 	return s.shoppingRecipeExclusionList
 }
 
+// Fetch the crafting quality data, if not available locally it will be empty
+func (s *StaticSources) GetCraftingQuality() *CraftingQualityCache {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
+	s.fillNames()
+	if s.craftingQuality == nil {
+		cq := CraftingQualityCache{}
+		fn := path.Join(environment_variables.STATIC_DIR_ROOT, s.RootDirectory, s.CraftingQualityFileName)
+		if err := loadStaticResource(fn, &cq); err != nil {
+			cq = CraftingQualityCache{}
+		}
+		s.craftingQuality = &cq
+	}
+	return s.craftingQuality
+}
+
+// Fetch the reagents available for each modified crafting slot, if not available locally it will be empty
+func (s *StaticSources) GetReagentSlots() *ReagentSlotsCache {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
+	s.fillNames()
+	if s.reagentSlots == nil {
+		rs := ReagentSlotsCache{}
+		fn := path.Join(environment_variables.STATIC_DIR_ROOT, s.RootDirectory, s.ReagentSlotsFileName)
+		if err := loadStaticResource(fn, &rs); err != nil {
+			rs = ReagentSlotsCache{}
+		}
+		s.reagentSlots = &rs
+	}
+	return s.reagentSlots
+}
+
+// Fetch the list of vendor sold items, if not available locally it will be empty
+func (s *StaticSources) GetVendorPrices() *VendorPricesCache {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
+	s.fillNames()
+	if s.vendorPrices == nil {
+		vp := VendorPricesCache{}
+		fn := path.Join(environment_variables.STATIC_DIR_ROOT, s.RootDirectory, s.VendorPricesFileName)
+		if err := loadStaticResource(fn, &vp); err != nil {
+			vp = VendorPricesCache{}
+		}
+		s.vendorPrices = &vp
+	}
+	return s.vendorPrices
+}
+
 // Fetch the crafting link table built by FireSong
 // https://us.forums.blizzard.com/en/blizzard/t/dragonflight-profession-recipes-crafted-item-id/37444/7
 // https://gist.github.com/Firesong25/cc294b9360ab37b01d2350cc266f73e5
 func (s *StaticSources) GetFireSongsCraftingLinkTable() (*FireSongCraftingLinkTable, error) {
+	sources_mutex.Lock()
+	defer sources_mutex.Unlock()
 	s.fillNames()
 	if s.firesongDFCrafting == nil {
 		fdc := FireSongCraftingLinkTable{}
@@ -81,7 +81,8 @@ func (ahs *AuctionHistoryServer) GetAuctions(ctx context.Context, item globalTyp
 	}
 
 	if connectedRealmId != 0 {
-		sql_addins = append(sql_addins, fmt.Sprintf(`connected_realm_id = %s`, get_place_marker()))
+		// A realm's prices include the region's commodities
+		sql_addins = append(sql_addins, fmt.Sprintf(`connected_realm_id IN (%s, %d)`, get_place_marker(), COMMODITY_REALM_ID))
 		value_searches = append(value_searches, connectedRealmId)
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
 )
 
+// Commodity listings are archived under this connected realm id, they belong to every realm of their region
+const COMMODITY_REALM_ID globalTypes.ConnectedRealmID = 0
+
 type ScanRealmsResult struct {
 	RealmNames string                       `json:"realm_names,omitempty"`
 	RealmId    globalTypes.ConnectedRealmID `json:"realm_id,omitempty"`
@@ -71,6 +75,7 @@ func (ahs *AuctionHistoryServer) ScanRealms(ctx context.Context, async bool) err
 	}
 	defer realms.Close()
 
+	regions := make([]globalTypes.RegionCode, 0)
 	for realms.Next() {
 		var (
 			connected_realm_id uint
@@ -81,6 +86,16 @@ func (ahs *AuctionHistoryServer) ScanRealms(ctx context.Context, async bool) err
 		if ingestErr != nil {
 			return ingestErr
 		}
+		if !slices.Contains(regions, strings.ToLower(region)) {
+			regions = append(regions, strings.ToLower(region))
+		}
+	}
+
+	// Commodities are shared by every realm of a region, so they are only ingested once per region
+	for _, region := range regions {
+		if ingestErr := ahs.ingestCommodities(ctx, region, async); ingestErr != nil {
+			return ingestErr
+		}
 	}
 
 	return nil
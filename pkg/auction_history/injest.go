@@ -8,11 +8,37 @@ import (
 	"time"
 
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
 	"github.com/jackc/pgx/v4"
 )
 
-// Injest a realm for auction archives
+// Injest a realm for auction archives, commodities are ingested separately once per region
 func (ahs *AuctionHistoryServer) ingest(ctx context.Context, region globalTypes.RegionCode, connected_realm globalTypes.ConnectedRealmID, async bool) error {
+	ahs.logger.Infof("start ingest for %v - %v", region, connected_realm)
+
+	// Get Auctions
+	auctions, auctionError := ahs.helper.GetRealmAuctions(ctx, connected_realm, region)
+	if auctionError != nil {
+		return auctionError
+	}
+
+	return ahs.ingestAuctions(ctx, region, connected_realm, auctions, async)
+}
+
+// Injest the region wide commodity market for auction archives, stored under COMMODITY_REALM_ID
+func (ahs *AuctionHistoryServer) ingestCommodities(ctx context.Context, region globalTypes.RegionCode, async bool) error {
+	ahs.logger.Infof("start commodity ingest for %v", region)
+
+	auctions, auctionError := ahs.helper.GetCommodityAuctions(ctx, region)
+	if auctionError != nil {
+		return auctionError
+	}
+
+	return ahs.ingestAuctions(ctx, region, COMMODITY_REALM_ID, auctions, async)
+}
+
+// Archive a set of auctions under a connected realm
+func (ahs *AuctionHistoryServer) ingestAuctions(ctx context.Context, region globalTypes.RegionCode, connected_realm globalTypes.ConnectedRealmID, auctions BlizzardApi.Auctions, async bool) error {
 	type lItm struct {
 		ItemId     globalTypes.ItemID
 		BonusLists []uint
@@ -21,14 +47,6 @@ func (ahs *AuctionHistoryServer) ingest(ctx context.Context, region globalTypes.
 	}
 	items := make(map[string]map[uint]lItm)
 
-	ahs.logger.Infof("start ingest for %v - %v", region, connected_realm)
-
-	// Get Auctions
-	auctions, auctionError := ahs.helper.GetAuctionHouse(ctx, connected_realm, region)
-	if auctionError != nil {
-		return auctionError
-	}
-
 	fetchTime := time.Now()
 
 	for _, auction := range auctions.Auctions {
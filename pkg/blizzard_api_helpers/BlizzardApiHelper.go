@@ -19,3 +19,20 @@ func NewBlizzardApiHelper(cache *cache_provider.CacheProvider, logger *cpclog.Cp
 		logger: logger,
 	}
 }
+
+/*
+Create a helper that never contacts Blizzard, every item, recipe, profession and realm lookup
+is answered from a local data file written by SaveLocalData.
+*/
+func NewOfflineBlizzardApiHelper(data_fn string, logger *cpclog.CpCLog) (*BlizzardApiHelper, error) {
+	cache := cache_provider.NewMemoryCacheProvider()
+	if err := cache.LoadDump(data_fn); err != nil {
+		return nil, err
+	}
+	return NewBlizzardApiHelper(cache, logger, nil), nil
+}
+
+// Save everything the helper has fetched so it can be used later with NewOfflineBlizzardApiHelper, the helper must use a memory cache
+func (helper *BlizzardApiHelper) SaveLocalData(data_fn string) error {
+	return helper.cache.SaveDump(data_fn)
+}
@@ -1,6 +1,7 @@
 package blizzard_api_helpers
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"slices"
@@ -29,12 +30,16 @@ const (
 	PROFESSION_RECIPE_DETAIL_CACHE       string = "fetched_profession_recipe_detail_data"
 	CRAFTABLE_BY_PROFESSION_SET_CACHE    string = "craftable_by_professions_cache"
 	CRAFTABLE_BY_SINGLE_PROFESSION_CACHE string = "craftable_by_profession"
-	AUCTION_DATA_CACHE                   string = "fetched_auctions_data"
+	REALM_AUCTION_DATA_CACHE             string = "fetched_realm_auctions_data"
+	COMMODITY_AUCTION_DATA_CACHE         string = "fetched_commodity_auctions_data"
 	PROFESSION_DETAIL_CACHE              string = "profession_detail_data"
 	PROFESSION_LIST_CACHE                string = "regional_profession_list"
 	COMPOSITE_REALM_NAME_CACHE           string = "connected_realm_detail"
 	CYCLIC_LINK_CACHE                    string = "cyclic_links"
 	ALL_REALM_NAMES_CACHE                string = "all_realm_names"
+	CRAFTED_ITEMS_BY_PROFESSION_CACHE    string = "crafted_items_by_profession"
+	REAGENT_SLOT_TYPE_CACHE              string = "fetched_reagent_slot_type_data"
+	MODIFIED_CRAFTING_CATEGORY_CACHE     string = "modified_crafting_category_items"
 )
 
 type basicDataPackage map[string]string
@@ -130,6 +135,42 @@ func (helper *BlizzardApiHelper) GetItemId(ctx context.Context, region globalTyp
 	return item_id, nil
 }
 
+// Find the item ids of every reagent in a modified crafting category
+func (helper *BlizzardApiHelper) GetModifiedCraftingCategoryItems(ctx context.Context, category_id uint, region globalTypes.RegionCode) ([]globalTypes.ItemID, error) {
+	key := fmt.Sprintf("%s::%d", region, category_id)
+
+	if found, err := cache_provider.CacheCheck(helper.cache, MODIFIED_CRAFTING_CATEGORY_CACHE, key); err == nil && found {
+		items := []globalTypes.ItemID{}
+		fndErr := cache_provider.CacheGet(helper.cache, MODIFIED_CRAFTING_CATEGORY_CACHE, key, &items)
+		return items, fndErr
+	}
+
+	const search_api_uri = "/data/wow/search/item"
+
+	items := []globalTypes.ItemID{}
+	for page, page_count := uint(1), uint(1); page <= page_count; page++ {
+		getPage := BlizzardApi.ItemSearch{}
+		err := blizzard_api_call.GetBlizzardAPIResponse(ctx, helper.api, region, searchPageDataPackage{
+			"locale":                        blizzard_api_call.ENGLISH_US,
+			"modified_crafting.category.id": fmt.Sprint(category_id),
+			"orderby":                       "id",
+			"_pageSize":                     searchPageSize,
+			"_page":                         fmt.Sprint(page),
+		}, search_api_uri, getNamespace(static_ns, region), &getPage)
+		if err != nil {
+			return nil, fmt.Errorf("search error for modified crafting category %d: %w", category_id, err)
+		}
+		page_count = getPage.PageCount
+		for _, result := range getPage.Results {
+			items = append(items, result.Data.Id)
+		}
+	}
+
+	cache_provider.CacheSet(helper.cache, MODIFIED_CRAFTING_CATEGORY_CACHE, key, items, cache_provider.GetStaticTimeWithShift())
+
+	return items, nil
+}
+
 // Get a list of all connected realms
 func (helper *BlizzardApiHelper) getAllConnectedRealms(ctx context.Context, region globalTypes.RegionCode) (BlizzardApi.ConnectedRealmIndex, error) {
 
@@ -270,6 +311,7 @@ func (helper *BlizzardApiHelper) CheckIsCrafting(ctx context.Context, item_id gl
 		recipe_options.Recipe_ids = append(recipe_options.Recipe_ids, profession_crafting_check.Recipe_ids...)
 		recipe_options.Craftable = recipe_options.Craftable || profession_crafting_check.Craftable
 	}
+	sortCraftingStatus(&recipe_options)
 
 	cache_provider.CacheSet(helper.cache, CRAFTABLE_BY_PROFESSION_SET_CACHE, key, &recipe_options, cache_provider.GetComputedTimeWithShift())
 	return recipe_options, nil
@@ -370,6 +412,20 @@ func (helper *BlizzardApiHelper) checkProfessionTierCrafting(ctx context.Context
 	return g.Wait()
 }
 
+// Recipes are found concurrently, order them by id so results do not depend on which lookup finished first
+func sortCraftingStatus(status *globalTypes.CraftingStatus) {
+	slices.SortStableFunc(status.Recipes, func(a, b struct {
+		Recipe_id           uint
+		Crafting_profession string
+	}) int {
+		if c := cmp.Compare(a.Recipe_id, b.Recipe_id); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Crafting_profession, b.Crafting_profession)
+	})
+	slices.Sort(status.Recipe_ids)
+}
+
 // Check whether an item can be crafted by a given profession
 func (helper *BlizzardApiHelper) checkProfessionCrafting(ctx context.Context, profession_id uint, prof globalTypes.CharacterProfession, region globalTypes.RegionCode, item_id globalTypes.ItemID, item_detail BlizzardApi.Item, static_source *static_sources.StaticSources) (globalTypes.CraftingStatus, error) {
 	cache_key := fmt.Sprintf("%s:%s:%d", region, prof, item_id)
@@ -402,6 +458,7 @@ func (helper *BlizzardApiHelper) checkProfessionCrafting(ctx context.Context, pr
 	if err := g.Wait(); err != nil {
 		return globalTypes.CraftingStatus{}, err
 	}
+	sortCraftingStatus(&profession_recipe_options)
 
 	cache_provider.CacheSet(helper.cache, CRAFTABLE_BY_SINGLE_PROFESSION_CACHE, cache_key, profession_recipe_options, cache_provider.GetComputedTimeWithShift())
 
@@ -409,6 +466,75 @@ func (helper *BlizzardApiHelper) checkProfessionCrafting(ctx context.Context, pr
 
 }
 
+// Get every item that can be crafted by a given profession, across all of its skill tiers
+func (helper *BlizzardApiHelper) GetProfessionCraftedItems(ctx context.Context, prof globalTypes.CharacterProfession, region globalTypes.RegionCode, static_source *static_sources.StaticSources) ([]globalTypes.ItemID, error) {
+	cache_key := fmt.Sprintf("%s::%s", region, prof)
+	if found, err := cache_provider.CacheCheck(helper.cache, CRAFTED_ITEMS_BY_PROFESSION_CACHE, cache_key); err == nil && found {
+		var items []globalTypes.ItemID
+		fndErr := cache_provider.CacheGet(helper.cache, CRAFTED_ITEMS_BY_PROFESSION_CACHE, cache_key, &items)
+		return items, fndErr
+	}
+
+	profession_list, err := helper.GetBlizProfessionsList(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	profession_id, err := getProfessionId(profession_list, prof)
+	if err != nil {
+		return nil, err
+	}
+	profession_detail, err := helper.GetBlizProfessionDetail(ctx, profession_id, region)
+	if err != nil {
+		return nil, err
+	}
+
+	crafted_items := util.NewSet[globalTypes.ItemID]()
+	var mutex sync.Mutex
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for _, tier := range profession_detail.Skill_tiers {
+		tier := tier
+		if exclude_before_shadowlands && !strings.Contains(tier.Name, "Shadowlands") {
+			continue
+		}
+		g.Go(func() error {
+			skill_tier_detail, err := helper.GetBlizSkillTierDetail(gCtx, profession_id, tier.Id, region)
+			if err != nil {
+				return err
+			}
+			for _, cat := range skill_tier_detail.Categories {
+				for _, rec := range cat.Recipes {
+					recipe, err := helper.GetBlizRecipeDetail(gCtx, rec.Id, region)
+					if err != nil {
+						return err
+					}
+					if strings.Contains(recipe.Name, "Prospect") || strings.Contains(recipe.Name, "Mill") {
+						continue
+					}
+					ids := getRecipeCraftedItemID(gCtx, recipe, region, helper, static_source)
+					mutex.Lock()
+					for _, id := range ids {
+						crafted_items.Add(id)
+					}
+					mutex.Unlock()
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	items := crafted_items.ToSlice()
+	slices.Sort(items)
+	helper.logger.Debugf("Profession %s can craft %d items", prof, len(items))
+
+	cache_provider.CacheSet(helper.cache, CRAFTED_ITEMS_BY_PROFESSION_CACHE, cache_key, items, cache_provider.GetComputedTimeWithShift())
+	return items, nil
+}
+
 // Get the ID of an item crafted by a given recipe. If multiple items are crafted return them all
 func getRecipeCraftedItemID(ctx context.Context, recipe BlizzardApi.Recipe, region globalTypes.RegionCode, helper *BlizzardApiHelper, static_source *static_sources.StaticSources) []globalTypes.ItemID {
 	item_ids := make(map[globalTypes.ItemID]bool)
@@ -3,6 +3,8 @@ package blizzard_api_helpers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/blizzard_api_call"
@@ -22,6 +24,7 @@ const (
 	getAuctionCommonditiesUri      string = "/data/wow/auctions/commodities"
 	getItemMediaUri                string = "/data/wow/media/item/%d"
 	getRecipeMediaUri               string = "/data/wow/media/recipe/%d"
+	getReagentSlotTypeUri          string = "/data/wow/modified-crafting/reagent-slot-type/%d"
 )
 
 // Fetch item details from Blizzard API
@@ -158,34 +161,86 @@ func (helper *BlizzardApiHelper) GetBlizRecipeDetail(ctx context.Context, recipe
 	return result, nil
 }
 
-// Return an auction house for a given realm and region from the Blizzard API
+// Fetch a modified crafting reagent slot type from Blizzard API
+func (helper *BlizzardApiHelper) GetBlizReagentSlotType(ctx context.Context, slot_type_id uint, region globalTypes.RegionCode) (BlizzardApi.ReagentSlotType, error) {
+	key := fmt.Sprintf("%s::%d", region, slot_type_id)
+
+	if found, err := cache_provider.CacheCheck(helper.cache, REAGENT_SLOT_TYPE_CACHE, key); err == nil && found {
+		item := BlizzardApi.ReagentSlotType{}
+		fndErr := cache_provider.CacheGet(helper.cache, REAGENT_SLOT_TYPE_CACHE, key, &item)
+		return item, fndErr
+	}
+
+	slot_type_uri := fmt.Sprintf(getReagentSlotTypeUri, slot_type_id)
+	result := BlizzardApi.ReagentSlotType{}
+	fetchErr := blizzard_api_call.GetBlizzardAPIResponse(ctx, helper.api, region, basicDataPackage{
+		"locale": blizzard_api_call.ENGLISH_US,
+	}, slot_type_uri, getNamespace(static_ns, region), &result)
+	if fetchErr != nil {
+		return BlizzardApi.ReagentSlotType{}, fetchErr
+	}
+	cache_provider.CacheSet(helper.cache, REAGENT_SLOT_TYPE_CACHE, key, &result, cache_provider.GetStaticTimeWithShift())
+	return result, nil
+}
+
+// Return an auction house for a given realm and region from the Blizzard API, the realm's listings followed by the region's commodities
 func (helper *BlizzardApiHelper) GetAuctionHouse(ctx context.Context, server_id globalTypes.ConnectedRealmID, server_region globalTypes.RegionCode) (BlizzardApi.Auctions, error) {
+	result, err := helper.GetRealmAuctions(ctx, server_id, server_region)
+	if err != nil {
+		return BlizzardApi.Auctions{}, err
+	}
+
+	commodities, comFetchErr := helper.GetCommodityAuctions(ctx, server_region)
+	if comFetchErr != nil {
+		// If commodities fail, we still return the main auctions but log the error
+		helper.logger.Errorf("Failed to fetch commodities: %v", comFetchErr)
+	} else {
+		result.Auctions = append(slices.Clip(result.Auctions), commodities.Auctions...)
+	}
+	return result, nil
+}
+
+// Return the listings of a single connected realm's auction house from the Blizzard API, commodities are not included
+func (helper *BlizzardApiHelper) GetRealmAuctions(ctx context.Context, server_id globalTypes.ConnectedRealmID, server_region globalTypes.RegionCode) (BlizzardApi.Auctions, error) {
 	key := fmt.Sprint(server_id)
 
-	if found, err := cache_provider.CacheCheck(helper.cache, AUCTION_DATA_CACHE, key); err == nil && found {
+	if found, err := cache_provider.CacheCheck(helper.cache, REALM_AUCTION_DATA_CACHE, key); err == nil && found {
 		item := BlizzardApi.Auctions{}
-		fndErr := cache_provider.CacheGet(helper.cache, AUCTION_DATA_CACHE, key, &item)
+		fndErr := cache_provider.CacheGet(helper.cache, REALM_AUCTION_DATA_CACHE, key, &item)
 		return item, fndErr
 	}
 
-	// Get the main auction house
 	auction_house_fetch_uri := fmt.Sprintf(getAuctionHouseUri, server_id)
 	result := BlizzardApi.Auctions{}
 	fetchErr := blizzard_api_call.GetBlizzardAPIResponse(ctx, helper.api, server_region, basicDataPackage{}, auction_house_fetch_uri, getNamespace(dynamic_ns, server_region), &result)
 	if fetchErr != nil {
 		return BlizzardApi.Auctions{}, fetchErr
 	}
-	auction_commondities_fetch_uri := getAuctionCommonditiesUri
-	commodities_result := BlizzardApi.Auctions{}
-	comFetchErr := blizzard_api_call.GetBlizzardAPIResponse(ctx, helper.api, server_region, basicDataPackage{}, auction_commondities_fetch_uri, getNamespace(dynamic_ns, server_region), &commodities_result)
-	if comFetchErr != nil {
-		// If commodities fail, we still return the main auctions but log the error
-		helper.logger.Errorf("Failed to fetch commodities: %v", comFetchErr)
-	} else {
-		result.Auctions = append(result.Auctions, commodities_result.Auctions...)
+
+	cache_provider.CacheSet(helper.cache, REALM_AUCTION_DATA_CACHE, key, &result, time.Duration(time.Hour*1))
+	return result, nil
+}
+
+// Return the region wide commodity listings from the Blizzard API, they are shared by every realm so are cached once per region
+func (helper *BlizzardApiHelper) GetCommodityAuctions(ctx context.Context, server_region globalTypes.RegionCode) (BlizzardApi.Auctions, error) {
+	key := strings.ToLower(server_region)
+
+	if found, err := cache_provider.CacheCheck(helper.cache, COMMODITY_AUCTION_DATA_CACHE, key); err == nil && found {
+		item := BlizzardApi.Auctions{}
+		fndErr := cache_provider.CacheGet(helper.cache, COMMODITY_AUCTION_DATA_CACHE, key, &item)
+		return item, fndErr
+	}
+
+	result := BlizzardApi.Auctions{}
+	fetchErr := blizzard_api_call.GetBlizzardAPIResponse(ctx, helper.api, server_region, basicDataPackage{}, getAuctionCommonditiesUri, getNamespace(dynamic_ns, server_region), &result)
+	if fetchErr != nil {
+		return BlizzardApi.Auctions{}, fetchErr
+	}
+	for i := range result.Auctions {
+		result.Auctions[i].Commodity = true
 	}
 
-	cache_provider.CacheSet(helper.cache, AUCTION_DATA_CACHE, key, &result, time.Duration(time.Hour*1))
+	cache_provider.CacheSet(helper.cache, COMMODITY_AUCTION_DATA_CACHE, key, &result, time.Duration(time.Hour*1))
 	return result, nil
 }
 
@@ -37,6 +37,7 @@ type Item struct {
 	Description       string               `json:"description,omitempty"`
 	Purchase_price    uint                 `json:"purchase_price,omitempty"`
 	Purchase_quantity uint                 `json:"purchase_quantity,omitempty"`
+	Sell_price        uint                 `json:"sell_price,omitempty"`
 	Level             uint                 `json:"level,omitempty"`
 	Item_class        struct {
 		Name string `json:"name,omitempty"`
@@ -86,6 +87,7 @@ type ProfessionSkillTier struct {
 type Recipe struct {
 	Id                    uint   `json:"id,omitempty"`
 	Name                  string `json:"name,omitempty"`
+	Rank                  uint   `json:"rank,omitempty"`
 	Alliance_crafted_item *struct {
 		Id uint `json:"id,omitempty"`
 	} `json:"alliance_crafted_item,omitempty"`
@@ -115,6 +117,16 @@ type Recipe struct {
 	} `json:"modified_crafting_slots,omitempty"`
 }
 
+// A modified crafting reagent slot type and the reagent categories that fit it
+type ReagentSlotType struct {
+	Id                    int    `json:"id,omitempty"`
+	Description           string `json:"description,omitempty"`
+	Compatible_categories []struct {
+		Name string `json:"name,omitempty"`
+		Id   int    `json:"id,omitempty"`
+	} `json:"compatible_categories,omitempty"`
+}
+
 type Auction struct {
 	Id   uint64 `json:"id,omitempty"`
 	Item struct {
@@ -131,6 +143,7 @@ type Auction struct {
 	Unit_price uint `json:"unit_price,omitempty"`
 	Bid        uint `json:"bid,omitempty"`
 	Time_left  string `json:"time_left,omitempty"`
+	Commodity  bool   `json:"commodity,omitempty"` // Set on region wide commodity listings, not part of the Blizzard API
 }
 
 type Auctions struct {
@@ -145,5 +158,5 @@ type Media struct {
 }
 
 type BlizzardApiReponse interface {
-	Auctions | Recipe | ProfessionSkillTier | Profession | ProfessionsIndex | Item | ConnectedRealm | ConnectedRealmIndex | ItemSearch | Media
+	Auctions | Recipe | ProfessionSkillTier | Profession | ProfessionsIndex | Item | ConnectedRealm | ConnectedRealmIndex | ItemSearch | Media | ReagentSlotType
 }
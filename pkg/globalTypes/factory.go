@@ -1,6 +1,10 @@
 package globalTypes
 
-import "strconv"
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
 
 func NewItemFromString(data string) ItemSoftIdentity {
 	number, err := strconv.ParseUint(data, 10, 64)
@@ -25,3 +29,12 @@ func NewRealmFromString(data string) ConnectedRealmSoftIentity {
 		Id: uint(number),
 	}
 }
+
+// Build a crafting plan from item ids or names and how many of each to make, ordered by the item string
+func NewPlanItems(data map[string]uint) []PlanItem {
+	plan_items := make([]PlanItem, 0, len(data))
+	for _, item := range slices.Sorted(maps.Keys(data)) {
+		plan_items = append(plan_items, PlanItem{Item: NewItemFromString(item), Count: data[item]})
+	}
+	return plan_items
+}
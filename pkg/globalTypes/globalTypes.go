@@ -29,11 +29,18 @@ type OutputFormatPrice struct {
 	Low     float64 `json:"low"`
 	Average float64 `json:"average"`
 	Median  float64 `json:"median"`
+	Price   float64 `json:"price,omitempty"`
+
+	Required            float64 `json:"required,omitempty"`
+	Fill_cost           float64 `json:"fill_cost,omitempty"`
+	Insufficient_supply bool    `json:"insufficient_supply,omitempty"`
+	Commodity           bool    `json:"commodity,omitempty"`
 }
 
 type ShoppingListCost struct {
-	Vendor float64           `json:"vendor"`
-	Ah     OutputFormatPrice `json:"ah"`
+	Vendor        float64           `json:"vendor"`
+	Vendor_source VendorPriceSource `json:"vendor_source,omitempty"`
+	Ah            OutputFormatPrice `json:"ah"`
 }
 
 type ShoppingList struct {
@@ -45,23 +52,70 @@ type ShoppingList struct {
 
 type OutputFormatShoppingList = map[uint][]ShoppingList
 
+// An intermediate or the target item to craft, steps are listed so every reagent is crafted before it is used
+type CraftStep struct {
+	Id        ItemID   `json:"id"`
+	Name      ItemName `json:"name"`
+	Recipe_id uint     `json:"recipe_id"`
+	Crafts    uint     `json:"crafts"`
+	Quantity  float64  `json:"quantity"` // Items the crafts are expected to make
+}
+
+type OutputFormatCraftSteps = map[uint][]CraftStep
+
+// What each rank's shopping list took from the inventory, reagents and intermediates alike
+type OutputFormatInventoryUsed = map[uint][]ReagentUse
+
 type OutpoutFormatRecipeOutput struct {
-	Min   float64 `json:"min"`
-	Max   float64 `json:"max"`
-	Value float64 `json:"value"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Value    float64 `json:"value"`
+	Expected float64 `json:"expected,omitempty"` // Average yield including multicraft
 }
 
 type OutputFormatRecipe struct {
-	Name    string                    `json:"name"`
-	Rank    uint                      `json:"rank"`
-	Id      uint                      `json:"id"`
-	Output  OutpoutFormatRecipeOutput `json:"output"`
-	Ah      OutputFormatPrice         `json:"ah"`
-	High    float64                   `json:"high"`
-	Low     float64                   `json:"low"`
-	Average float64                   `json:"average"`
-	Median  float64                   `json:"median"`
-	Parts   []OutputFormatObject      `json:"parts"`
+	Name         string                    `json:"name"`
+	Rank         uint                      `json:"rank"`
+	Id           uint                      `json:"id"`
+	Output       OutpoutFormatRecipeOutput `json:"output"`
+	Ah           OutputFormatPrice         `json:"ah"`
+	High         float64                   `json:"high"`
+	Low          float64                   `json:"low"`
+	Average      float64                   `json:"average"`
+	Median       float64                   `json:"median"`
+	Optimal_cost float64                   `json:"optimal_cost,omitempty"`
+	Parts        []OutputFormatObject      `json:"parts"`
+
+	Price float64 `json:"price"` // Cost of the reagents for a craft at the run's pricing strategy
+
+	Unavailable bool `json:"unavailable,omitempty"` // A part can neither be bought nor made, so the recipe cannot be crafted
+
+	Reagent_savings    float64 `json:"reagent_savings,omitempty"`    // Expected fraction of reagents saved by resourcefulness
+	Unit_cost          float64 `json:"unit_cost,omitempty"`          // Cost per item made, ignoring crafter stats
+	Expected_unit_cost float64 `json:"expected_unit_cost,omitempty"` // Cost per item made, including multicraft and resourcefulness
+
+	Slots []OutputFormatSlot `json:"slots,omitempty"`
+}
+
+type OutputFormatSlot struct {
+	Id       uint                     `json:"id"`
+	Name     string                   `json:"name"`
+	Required bool                     `json:"required,omitempty"`
+	Quantity uint                     `json:"quantity"`
+	Chosen   ItemID                   `json:"chosen,omitempty"`
+	Options  []OutputFormatSlotOption `json:"options,omitempty"`
+}
+
+type OutputFormatSlotOption struct {
+	Id ItemID            `json:"id"`
+	Ah OutputFormatPrice `json:"ah"`
+}
+
+type OutputFormatAcquisition struct {
+	Method     AcquisitionMethod `json:"method"`
+	Unit_cost  float64           `json:"unit_cost"`
+	Total_cost float64           `json:"total_cost"`
+	Recipe_id  uint              `json:"recipe_id,omitempty"`
 }
 
 type OutputFormatBonusPrices struct {
@@ -69,15 +123,50 @@ type OutputFormatBonusPrices struct {
 	Ah    OutputFormatPrice `json:"ah"`
 }
 
+type OutputFormatConversionStep struct {
+	Id    ItemID   `json:"id"`
+	Name  ItemName `json:"name"`
+	Takes float64  `json:"takes"`
+	Makes float64  `json:"makes"`
+}
+
+type OutputFormatConversion struct {
+	Source_id   ItemID                       `json:"source_id"`
+	Source_name ItemName                     `json:"source_name"`
+	Ratio       float64                      `json:"ratio"`
+	Unit_cost   float64                      `json:"unit_cost"`
+	Ah          OutputFormatPrice            `json:"ah"`
+	Vendor      float64                      `json:"vendor,omitempty"`
+	Chain       []OutputFormatConversionStep `json:"chain"`
+}
+
 type OutputFormatObject struct {
-	Name           string                    `json:"name,omitempty"`
-	Id             uint                      `json:"id,omitempty"`
-	Required       float64                   `json:"required,omitempty"`
-	Recipes        []OutputFormatRecipe      `json:"recipes"`
-	Ah             OutputFormatPrice         `json:"ah"`
-	Vendor         float64                   `json:"vendor,omitempty"`
-	Bonus_prices   []OutputFormatBonusPrices `json:"bonus_prices,omitempty"`
-	Shopping_lists OutputFormatShoppingList  `json:"shopping_lists,omitempty"`
+	Name           string                     `json:"name,omitempty"`
+	Id             uint                       `json:"id,omitempty"`
+	Required       float64                    `json:"required,omitempty"`
+	Recipes        []OutputFormatRecipe       `json:"recipes"`
+	Ah             OutputFormatPrice          `json:"ah"`
+	Vendor         float64                    `json:"vendor,omitempty"`
+	Conversion     *OutputFormatConversion    `json:"conversion,omitempty"`
+	Optimal        *OutputFormatAcquisition   `json:"optimal,omitempty"`
+	Bonus_prices   []OutputFormatBonusPrices  `json:"bonus_prices,omitempty"`
+	Quality_tier   uint                       `json:"quality_tier,omitempty"`
+	Quality_prices []OutputFormatQualityPrice `json:"quality_prices,omitempty"`
+	Shopping_lists OutputFormatShoppingList   `json:"shopping_lists,omitempty"`
+	Optimal_list   []ShoppingList             `json:"optimal_shopping_list,omitempty"`
+	Craft_steps    OutputFormatCraftSteps     `json:"craft_steps,omitempty"`
+	Optimal_steps  []CraftStep                `json:"optimal_craft_steps,omitempty"`
+	Inventory_used OutputFormatInventoryUsed  `json:"inventory_used,omitempty"`
+	Optimal_used   []ReagentUse               `json:"optimal_inventory_used,omitempty"`
+	Pruned         string                     `json:"pruned,omitempty"`
+	Vendor_source  VendorPriceSource          `json:"vendor_source,omitempty"`
+}
+
+// Auction prices for one crafting quality tier of an item
+type OutputFormatQualityPrice struct {
+	Tier uint              `json:"tier"`
+	Id   ItemID            `json:"id"`
+	Ah   OutputFormatPrice `json:"ah"`
 }
 
 type AHItemPriceObject struct {
@@ -86,6 +175,15 @@ type AHItemPriceObject struct {
 	Median      float64
 	High        float64
 	Low         float64
+	Price       float64 // Unit price chosen by the run's pricing strategy
+
+	Required            float64 // Units needed across the whole run
+	Fill_cost           float64 // Cost of buying the cheapest Required units, or every unit listed when supply runs short
+	Insufficient_supply bool    // Fewer than Required units are listed
+	Commodity           bool    // Listed on the region wide commodity market rather than the realm's auction house
+
+	Item_id ItemID // The item and bonus list priced, so the price can be walked again for another quantity
+	Bonus   uint
 }
 
 type RecipeOption struct {
@@ -94,31 +192,223 @@ type RecipeOption struct {
 		Recipe_id           uint
 		Crafting_profession string
 	}
-	Rank    uint
-	Rank_ah AHItemPriceObject
+	Rank             uint
+	Rank_ah          AHItemPriceObject
+	Crafted_quantity float64
+	Optimal_cost     float64
+	Unavailable      bool // A part can neither be bought nor made, Optimal_cost is left at 0
+
+	Expected_quantity float64 // Average yield of a craft including multicraft
+	Reagent_savings   float64 // Expected fraction of reagents saved by resourcefulness
+
+	Slots []RecipeSlot
+}
+
+// An optional, finishing or spark reagent slot on a recipe and the reagents that fit it
+type RecipeSlot struct {
+	Slot_type uint
+	Name      string
+	Required  bool
+	Quantity  uint
+	Chosen    ItemID // The reagent crafted with, 0 when the slot is left empty
+	Options   []SlotReagentPrice
+}
+
+type SlotReagentPrice struct {
+	Item_id ItemID
+	Ah      AHItemPriceObject
+}
+
+// How an item in the crafting tree should be acquired
+type AcquisitionMethod = string
+
+const (
+	ACQUIRE_BUY_AH      AcquisitionMethod = "buy_ah"
+	ACQUIRE_BUY_VENDOR  AcquisitionMethod = "buy_vendor"
+	ACQUIRE_CONVERT     AcquisitionMethod = "convert"
+	ACQUIRE_CRAFT       AcquisitionMethod = "craft"
+	ACQUIRE_UNAVAILABLE AcquisitionMethod = "unavailable"
+)
+
+// The cheapest way to get an item, Recipe_id is only set when crafting
+type AcquisitionDecision struct {
+	Method     AcquisitionMethod
+	Unit_cost  float64
+	Total_cost float64
+	Recipe_id  uint
+}
+
+// A single hop through a cyclic conversion, Takes of Id are consumed to Makes of the item before it in the chain
+type CyclicConversionStep struct {
+	Id    ItemID
+	Name  ItemName
+	Takes float64
+	Makes float64
+}
+
+// The cheapest way to acquire an item that is part of a recipe cycle
+type CyclicConversion struct {
+	Source_id     ItemID
+	Source_name   ItemName
+	Ratio         float64
+	Unit_cost     float64
+	Source_ah     AHItemPriceObject
+	Source_vendor float64
+	Chain         []CyclicConversionStep
 }
 
 type ProfitAnalysisObject struct {
-	Item_id         uint
-	Item_name       string
-	Ah_price        AHItemPriceObject
-	Item_quantity   float64
-	Vendor_price    float64
-	Crafting_status CraftingStatus
-	Bonus_lists     [][]uint
-	Recipe_options  []RecipeOption
-	Bonus_prices    []struct {
+	Item_id           uint
+	Item_name         string
+	Ah_price          AHItemPriceObject
+	Item_quantity     float64
+	Vendor_price      float64
+	Sell_price        float64
+	Crafting_status   CraftingStatus
+	Cyclic_conversion *CyclicConversion
+	Unpriced          bool // Nothing in the item's conversion cycle is sold, so it has no price and is left out of recipe costs
+	Acquisition       *AcquisitionDecision
+	Bonus_lists       [][]uint
+	Recipe_options    []RecipeOption
+	Bonus_prices      []struct {
 		Level uint
 		Ah    AHItemPriceObject
 	}
+	Quality_tier   uint // Crafting quality tier of the item, 0 when it has none
+	Quality_prices []QualityTierPrice
+	Pruned         string // Why the item's recipes were not analyzed, empty unless the run pruned it
+	Vendor_source  VendorPriceSource
+}
+
+// Where a vendor price came from
+type VendorPriceSource = string
+
+const (
+	VENDOR_PRICE_OVERRIDE    VendorPriceSource = "run_override"     // The run configuration's vendor prices
+	VENDOR_PRICE_STATIC      VendorPriceSource = "static_list"      // The maintained list of vendor sold items
+	VENDOR_PRICE_DESCRIPTION VendorPriceSource = "item_description" // Guess from the item's description for items on neither list, only when the run asks for it
+)
+
+// A reagent the run bought instead of crafting, because of the run's depth or value limits
+type PrunedNode struct {
+	Item_id   uint   `json:"item_id"`
+	Item_name string `json:"item_name"`
+	Depth     uint   `json:"depth"`
+	Reason    string `json:"reason"`
+}
+
+// Auction prices for one crafting quality tier, reagent tiers are separate items while crafted items share an id
+type QualityTierPrice struct {
+	Tier    uint
+	Item_id ItemID
+	Ah      AHItemPriceObject
+}
+
+// Expected profit from crafting and selling a single unit with one recipe rank
+type ProfitReport struct {
+	Recipe_id        uint    `json:"recipe_id"`
+	Rank             uint    `json:"rank"`
+	Sale_price       float64 `json:"sale_price"`
+	Crafting_cost    float64 `json:"crafting_cost"`
+	Naive_cost       float64 `json:"naive_crafting_cost,omitempty"` // Crafting cost ignoring multicraft and resourcefulness
+	Ah_cut           float64 `json:"ah_cut"`
+	Deposit          float64 `json:"deposit"`
+	Net_profit       float64 `json:"net_profit"`
+	Roi              float64 `json:"roi"`
+	Quantity         float64 `json:"quantity"`
+	Total_net_profit float64 `json:"total_net_profit"`
+}
+
+// What selling at a chosen price means for one recipe rank
+type WhatIfReport struct {
+	Recipe_id    uint            `json:"recipe_id"`
+	Rank         uint            `json:"rank"`
+	Sale_price   float64         `json:"sale_price"`
+	Unit_cost    float64         `json:"unit_cost"`  // Cost of each item made at current reagent prices
+	Net_profit   float64         `json:"net_profit"` // Profit per item at current reagent prices, after the auction house cut and deposit
+	Reagents     []WhatIfReagent `json:"reagents"`
+	Max_crafts   uint            `json:"max_crafts"`          // Crafts that can be made before the next one would lose money or run out of supply
+	Max_quantity float64         `json:"max_quantity"`        // Items those crafts are expected to make
+	Unlimited    bool            `json:"unlimited,omitempty"` // No reagent is limited by auction house supply, so there is no maximum
+}
+
+// The most a single reagent can cost before crafting at the chosen sale price stops being profitable
+type WhatIfReagent struct {
+	Id         ItemID            `json:"id"`
+	Name       ItemName          `json:"name"`
+	Quantity   float64           `json:"quantity"` // Used per craft
+	Unit_cost  float64           `json:"unit_cost"`
+	Break_even float64           `json:"break_even"` // Highest unit price that still breaks even with every other reagent at its current cost
+	Method     AcquisitionMethod `json:"method"`
+}
+
+// Which craftable intermediates a shopping list crafts rather than buys
+type IntermediatePolicy = string
+
+const (
+	INTERMEDIATES_MAKE_VS_BUY IntermediatePolicy = "make_vs_buy" // Follow the make vs. buy analysis
+	INTERMEDIATES_BUY         IntermediatePolicy = "buy"         // Buy every intermediate that is sold
+	INTERMEDIATES_CRAFT       IntermediatePolicy = "craft"       // Craft every intermediate
+)
+
+// The most of an item a budget can craft with one recipe rank and intermediate policy
+type BudgetOption struct {
+	Recipe_id     uint               `json:"recipe_id"`
+	Rank          uint               `json:"rank"`
+	Intermediates IntermediatePolicy `json:"intermediates"`
+	Quantity      uint               `json:"quantity"`
+	Cost          float64            `json:"cost"`
+	Shopping_list []ShoppingList     `json:"shopping_list,omitempty"`
+	Craft_steps   []CraftStep        `json:"craft_steps,omitempty"`
+}
+
+type BudgetReport struct {
+	Budget  float64        `json:"budget"`
+	Best    BudgetOption   `json:"best"` // The option crafting the most, ties go to the cheapest
+	Options []BudgetOption `json:"options"`
 }
 
 type RunReturn struct {
 	Price        ProfitAnalysisObject `json:"-"`
 	Intermediate OutputFormatObject   `json:"intermediate"`
+	Profits      []ProfitReport       `json:"profits,omitempty"`
+	What_if      []WhatIfReport       `json:"what_if,omitempty"`
+	Budget       *BudgetReport        `json:"budget,omitempty"`
+	Pricing      string               `json:"pricing_strategy,omitempty"`
+	Pruned       []PrunedNode         `json:"pruned,omitempty"`
+	Stats        RunStats             `json:"stats"`
+	Trace        []TraceEvent         `json:"trace,omitempty"`
 	Formatted    string               `json:"formatted,omitempty"`
 }
 
+// The kind of decision an explain trace event records
+type TraceKind = string
+
+const (
+	TRACE_AUCTIONS      TraceKind = "auctions"       // Auctions matched for an item and bonus, and the price chosen from them
+	TRACE_VENDOR_PRICE  TraceKind = "vendor_price"   // Which vendor price heuristic applied to an item
+	TRACE_RECIPES_FOUND TraceKind = "recipes_found"  // Recipes found for an item by each profession
+	TRACE_RANK          TraceKind = "rank"           // How a recipe's rank was assigned
+	TRACE_EXCLUSION     TraceKind = "exclusion"      // A recipe left out of shopping lists by the exclusion list
+	TRACE_PRUNED        TraceKind = "pruned"         // A reagent bought rather than crafted because of the run's limits
+	TRACE_CYCLIC        TraceKind = "cyclic_reagent" // A reagent priced through its conversion cycle instead of its recipes
+)
+
+// A single decision made during a run in explain mode
+type TraceEvent struct {
+	Kind      TraceKind      `json:"kind"`
+	Item_id   uint           `json:"item_id,omitempty"`
+	Recipe_id uint           `json:"recipe_id,omitempty"`
+	Detail    string         `json:"detail"`
+	Values    map[string]any `json:"values,omitempty"`
+}
+
+// How much work a run did, Memo_hits counts reagent analyses reused rather than computed again
+type RunStats struct {
+	Analyses  uint `json:"analyses"`
+	Memo_hits uint `json:"memo_hits"`
+}
+
 type ItemSoftIdentity struct {
 	ItemName string
 	ItemId   uint
@@ -129,14 +419,154 @@ type ConnectedRealmSoftIentity struct {
 	Name ConnectedRealmName
 }
 
+// The kind of work a queued job should do
+type RunMode = string
+
+const (
+	RUN_MODE_ITEM      RunMode = ""
+	RUN_MODE_SCAN      RunMode = "scan"
+	RUN_MODE_ARBITRAGE RunMode = "arbitrage"
+	RUN_MODE_ORDER     RunMode = "order"
+	RUN_MODE_PLAN      RunMode = "plan"
+)
+
+type RunJobConfig struct {
+	Mode              RunMode
+	Item              ItemSoftIdentity
+	Count             uint
+	UseAllProfessions bool
+	AddonData         AddonData
+	Limit             uint
+	Pricing_strategy  string
+	Reagent_quality   uint
+	Crafter_stats     map[CharacterProfession]CrafterStats
+	Slot_reagents     map[uint]ItemID
+	Max_depth         uint
+	Raw_materials     []ItemID
+	Min_craft_value   float64
+	Explain           bool
+	Vendor_prices     map[ItemID]float64
+	Guess_vendor      bool
+	Target_price      float64
+	Undercut_percent  float64
+	Order_reagents    map[ItemID]uint
+	Commission        float64
+	Plan_items        []PlanItem
+	Budget            float64
+}
+
+// A reagent taken from an inventory rather than bought
+type ReagentUse struct {
+	Id       ItemID   `json:"id"`
+	Name     ItemName `json:"name"`
+	Quantity float64  `json:"quantity"`
+}
+
+// The crafter's side of a crafting order for one recipe rank
+type CraftingOrderReport struct {
+	Recipe_id     uint           `json:"recipe_id"`
+	Rank          uint           `json:"rank"`
+	Provided      []ReagentUse   `json:"provided"`      // Customer reagents the craft uses
+	Shopping_list []ShoppingList `json:"shopping_list"` // Everything the crafter still has to buy
+	Out_of_pocket float64        `json:"out_of_pocket"`
+	Net_profit    float64        `json:"net_profit"`
+
+	Unbuyable []ReagentUse `json:"unbuyable,omitempty"` // On the shopping list but sold by no vendor or auction, left out of Out_of_pocket
+}
+
+type CraftingOrderReturn struct {
+	Item_id    ItemID                `json:"item_id"`
+	Item_name  ItemName              `json:"item_name"`
+	Commission float64               `json:"commission"`
+	Ranks      []CraftingOrderReport `json:"ranks"`
+	Stats      RunStats              `json:"stats"`
+	Formatted  string                `json:"formatted,omitempty"`
+}
+
+// The make vs. buy cost of one item of a crafting plan
+type PlanItemResult struct {
+	Item_id    ItemID   `json:"item_id"`
+	Item_name  ItemName `json:"item_name"`
+	Count      uint     `json:"count"`
+	Unit_cost  float64  `json:"unit_cost"`
+	Total_cost float64  `json:"total_cost"`
+}
+
+type PlanReturn struct {
+	Items         []PlanItemResult `json:"items"`
+	Shopping_list []ShoppingList   `json:"shopping_list"` // Everything still to buy for the whole plan, after the inventory is used
+	Craft_steps   []CraftStep      `json:"craft_steps"`
+	Total_cost    float64          `json:"total_cost"`
+	Used          []ReagentUse     `json:"used"`     // Taken from the inventory
+	Leftover      []ReagentUse     `json:"leftover"` // Still in the inventory once the plan is crafted
+	Stats         RunStats         `json:"stats"`
+	Formatted     string           `json:"formatted,omitempty"`
+
+	Unbuyable []ReagentUse `json:"unbuyable,omitempty"` // On the shopping list but sold by no vendor or auction, left out of Total_cost
+}
+
 type RunJob struct {
 	JobId     string
-	JobConfig struct {
-		Item              ItemSoftIdentity
-		Count             uint
-		UseAllProfessions bool
-		AddonData         AddonData
-	}
+	JobConfig RunJobConfig
+}
+
+// A single crafted item from a profession scan, Best is the most profitable rank
+type CraftScanResult struct {
+	Item_id   ItemID         `json:"item_id"`
+	Item_name ItemName       `json:"item_name"`
+	Best      ProfitReport   `json:"best"`
+	Profits   []ProfitReport `json:"profits"`
+}
+
+type ScanReturn struct {
+	Results   []CraftScanResult `json:"results"`
+	Scanned   uint              `json:"scanned"`
+	Stats     RunStats          `json:"stats"`
+	Formatted string            `json:"formatted,omitempty"`
+}
+
+// A scanned realm's prices for the reagents of a craft and for the crafted item
+type ArbitrageRealm struct {
+	Id                 ConnectedRealmID `json:"id"`
+	Name               string           `json:"name"`
+	Reagent_cost       float64          `json:"reagent_cost"`
+	Reagents_available bool             `json:"reagents_available"`
+	Sale_price         float64          `json:"sale_price"`
+	Sales              uint             `json:"sales"`
+}
+
+// A reagent of the craft and where it is cheapest to buy
+type ArbitrageReagent struct {
+	Id             ItemID                       `json:"id"`
+	Name           ItemName                     `json:"name"`
+	Quantity       float64                      `json:"quantity"`
+	Vendor         float64                      `json:"vendor,omitempty"`
+	Prices         map[ConnectedRealmID]float64 `json:"prices"`
+	Cheapest_realm ConnectedRealmID             `json:"cheapest_realm,omitempty"`
+	Cheapest_price float64                      `json:"cheapest_price"`
+}
+
+// The profit of buying every reagent on one realm and selling the crafted items on another
+type ArbitrageCell struct {
+	Buy_realm    ConnectedRealmID `json:"buy_realm"`
+	Sell_realm   ConnectedRealmID `json:"sell_realm"`
+	Reagent_cost float64          `json:"reagent_cost"`
+	Sale_value   float64          `json:"sale_value"`
+	Net_profit   float64          `json:"net_profit"`
+}
+
+type ArbitrageReturn struct {
+	Item_id    ItemID             `json:"item_id"`
+	Item_name  ItemName           `json:"item_name"`
+	Quantity   float64            `json:"quantity"`
+	Realms     []ArbitrageRealm   `json:"realms"`
+	Reagents   []ArbitrageReagent `json:"reagents"`
+	Matrix     []ArbitrageCell    `json:"matrix"`
+	Best       *ArbitrageCell     `json:"best,omitempty"`
+	Split_cost float64            `json:"split_cost,omitempty"` // Buying every reagent on its cheapest realm
+	Split_net  float64            `json:"split_net_profit,omitempty"`
+	Best_sell  ConnectedRealmID   `json:"best_sell_realm,omitempty"`
+	Formatted  string             `json:"formatted,omitempty"`
 }
 
 type ReturnError struct {
@@ -144,7 +574,16 @@ type ReturnError struct {
 }
 
 type QueuedJobReturn struct {
-	JobId string `json:"job_id"`
+	JobId    string       `json:"job_id"`
+	Progress *RunProgress `json:"progress,omitempty"`
+}
+
+// How far a run has got, reported while it is still running
+type RunProgress struct {
+	Items_analyzed uint   `json:"items_analyzed"`
+	Api_calls      uint64 `json:"api_calls"`
+	Depth          uint   `json:"depth"`          // Reagent depth of the item most recently analyzed
+	Item_name      string `json:"item,omitempty"` // The item most recently analyzed
 }
 
 var ALL_PROFESSIONS []CharacterProfession = []CharacterProfession{"Blacksmithing", "Leatherworking", "Alchemy", "Herbalism", "Cooking", "Mining", "Tailoring", "Engineering", "Enchanting", "Fishing", "Skinning", "Jewelcrafting", "Inscription", "Archaeology", "Soul Cyphering", "Abominable Stitching", "Ascension Crafting", "Stygia Crafting"}
@@ -153,4 +592,5 @@ var ALL_PROFESSIONS []CharacterProfession = []CharacterProfession{"Blacksmithing
 const (
 	CPC_JOB_QUEUE_NAME           = "cpc-job-queue:web-jobs"
 	CPC_JOB_RETURN_FORMAT_STRING = "cpc-job-queue-results:%s"
+	CPC_JOB_PROGRESS_KEY_FORMAT  = "cpc-job-queue-progress:%s"
 )
@@ -1,5 +1,7 @@
 package globalTypes
 
+import "maps"
+
 type AddonData struct {
 	Inventory []struct {
 		Id       ItemID `json:"id,omitempty"`
@@ -16,19 +18,64 @@ type AddonData struct {
 
 type RunConfiguration struct {
 	internal_inventory map[ItemID]uint       //`json:"internal_inventory,omitempty"`
-	inventory_overlay  map[ItemID]int        //`json:"inventory_overlay,omitempty"`
+	inventory_used     map[ItemID]float64    // Ledger of what the run has taken from internal_inventory
 	UseAllProfessions  bool                  `json:"use_all_professions"`
 	Professions        []CharacterProfession `json:"professions,omitempty"`
 	Realm_name         RealmName             `json:"realm_name,omitempty"`
 	Realm_region       RegionCode            `json:"realm_region,omitempty"`
 	Item               ItemSoftIdentity      `json:"item"`
 	Item_count         uint                  `json:"item_count,omitempty"`
+	Listing_duration   uint                  `json:"listing_duration,omitempty"`
+	Pricing_strategy   string                `json:"pricing_strategy,omitempty"`
+	// Reagent quality tier to craft with, 0 picks the cheapest tier. Recipe_reagent_quality overrides it per recipe id.
+	Reagent_quality        uint          `json:"reagent_quality,omitempty"`
+	Recipe_reagent_quality map[uint]uint `json:"recipe_reagent_quality,omitempty"`
+	// The crafter's secondary stats for each profession, used to estimate expected yields and costs
+	Crafter_stats map[CharacterProfession]CrafterStats `json:"crafter_stats,omitempty"`
+	// The reagent to put in each modified crafting slot, keyed by slot type id. 0 leaves a slot empty.
+	Slot_reagents map[uint]ItemID `json:"slot_reagents,omitempty"`
+	// Limits on how far reagents are broken down into their own recipes, pruned reagents are bought instead
+	Max_depth       uint     `json:"max_depth,omitempty"`       // Levels of reagents to craft, 0 for no limit
+	Raw_materials   []ItemID `json:"raw_materials,omitempty"`   // Reagents that are always bought
+	Min_craft_value float64  `json:"min_craft_value,omitempty"` // Reagents cheaper than this on the auction house are bought
+	// Vendor prices per unit in copper that override the static vendor list, 0 marks an item as not sold by vendors
+	Vendor_prices map[ItemID]float64 `json:"vendor_prices,omitempty"`
+	// Guess from the item's English description whether vendors sell items on neither vendor list, otherwise they are not vendor sold
+	Guess_vendor_prices bool `json:"guess_vendor_prices,omitempty"`
+	// What if the item sold at Target_price in copper, or Undercut_percent below the current lowest listing
+	Target_price     float64 `json:"target_price,omitempty"`
+	Undercut_percent float64 `json:"undercut_percent,omitempty"`
+	// A crafting order, the reagents the customer provides and the commission they pay in copper
+	Order_reagents map[ItemID]uint `json:"order_reagents,omitempty"`
+	Commission     float64         `json:"commission,omitempty"`
+	// Copper to spend, the run reports how many of the item it can craft and how
+	Budget float64 `json:"budget,omitempty"`
+	// Items to craft together as one plan, sharing the inventory
+	Plan_items []PlanItem `json:"plan_items,omitempty"`
+	// Record every pricing, recipe and rank decision the run makes
+	Explain bool `json:"explain,omitempty"`
+	// Also save the shopping list as an in-game addon import string in this format
+	Export_format string `json:"export_format,omitempty"`
+}
+
+// A crafter's secondary stats for a single profession, all values are percentages
+type CrafterStats struct {
+	Multicraft              float64 `json:"multicraft,omitempty"`              // Chance a craft makes extra items
+	Resourcefulness         float64 `json:"resourcefulness,omitempty"`         // Chance a craft refunds some of its reagents
+	Multicraft_bonus        float64 `json:"multicraft_bonus,omitempty"`        // Average extra yield of a multicraft proc relative to the base yield, 125 when unset
+	Resourcefulness_savings float64 `json:"resourcefulness_savings,omitempty"` // Average share of reagents a resourcefulness proc refunds, 30 when unset
+}
+
+// One item of a crafting plan and how many of it to make
+type PlanItem struct {
+	Item  ItemSoftIdentity `json:"item"`
+	Count uint             `json:"count"`
 }
 
 func NewRunConfig(raw_configuration_data *AddonData, item ItemSoftIdentity, count uint) (new_conf *RunConfiguration) {
 	new_conf = &RunConfiguration{}
 	new_conf.internal_inventory = make(map[uint]uint)
-	new_conf.inventory_overlay = make(map[uint]int)
+	new_conf.inventory_used = make(map[ItemID]float64)
 	if raw_configuration_data != nil {
 		for _, item := range raw_configuration_data.Inventory {
 			new_conf.internal_inventory[item.Id] = item.Quantity
@@ -44,31 +91,55 @@ func NewRunConfig(raw_configuration_data *AddonData, item ItemSoftIdentity, coun
 	return
 }
 
-func (rc RunConfiguration) ItemIsInInventory(item_id ItemID) bool {
-	_, present := rc.internal_inventory[item_id]
-	return present
+// What is left of an inventory item after everything the run has taken, fractional when averages were taken
+func (rc RunConfiguration) InventoryRemaining(item_id ItemID) float64 {
+	return float64(rc.internal_inventory[item_id]) - rc.inventory_used[item_id]
 }
 
-func (rc RunConfiguration) ItemCount(item_id ItemID) uint {
-	available := 0
-	if rc.ItemIsInInventory(item_id) {
-		available += int(rc.internal_inventory[item_id])
+/*
+Take up to wanted of an item from the inventory, recording it in the ledger.
+Returns how much was taken, which is less than wanted when the inventory runs out.
+*/
+func (rc *RunConfiguration) UseInventory(item_id ItemID, wanted float64) float64 {
+	taken := min(wanted, rc.InventoryRemaining(item_id))
+	if taken <= 0 {
+		return 0
 	}
-	if delta, has_overlay := rc.inventory_overlay[item_id]; has_overlay {
-		available += delta
+	if rc.inventory_used == nil {
+		rc.inventory_used = make(map[ItemID]float64)
 	}
+	rc.inventory_used[item_id] += taken
+	return taken
+}
 
-	return uint(available)
+// What is left of each inventory item after everything the run has taken, items that ran out are left out
+func (rc RunConfiguration) RemainingInventory() map[ItemID]float64 {
+	remaining := make(map[ItemID]float64)
+	for item_id := range rc.internal_inventory {
+		if count := rc.InventoryRemaining(item_id); count > 0 {
+			remaining[item_id] = count
+		}
+	}
+	return remaining
 }
 
-func (rc *RunConfiguration) AdjustInventory(item_id ItemID, adjustment_delta int) {
-	/*
-		if (!(item_id in this.#inventory_overlay)) {
-			this.#inventory_overlay[item_id] = 0;
-		}*/
-	rc.inventory_overlay[item_id] += adjustment_delta
+// Put everything the run has taken back into the inventory
+func (rc *RunConfiguration) ResetInventoryLedger() {
+	rc.inventory_used = make(map[ItemID]float64)
 }
 
-func (rc *RunConfiguration) ResetInventoryAdjustments() {
-	rc.inventory_overlay = make(map[uint]int)
+// A copy of the run configuration with its own ledger, plans tried against it leave this one's ledger alone
+func (rc RunConfiguration) InventoryCopy() *RunConfiguration {
+	inventory := rc
+	inventory.inventory_used = maps.Clone(rc.inventory_used)
+	return &inventory
+}
+
+// An inventory holding only the reagents the customer provides for a crafting order
+func (rc RunConfiguration) OrderInventory() *RunConfiguration {
+	order := NewRunConfig(nil, rc.Item, rc.Item_count)
+	for item_id, quantity := range rc.Order_reagents {
+		order.internal_inventory[item_id] = quantity
+	}
+	return order
 }
@@ -0,0 +1,42 @@
+package globalTypes
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUseInventory(t *testing.T) {
+	decoded := &RunConfiguration{}
+	if err := json.Unmarshal([]byte(`{"item":{"ItemId":1001},"item_count":1}`), decoded); err != nil {
+		t.Fatal(err)
+	}
+	addon := AddonData{}
+	if err := json.Unmarshal([]byte(`{"inventory":[{"id":2001,"quantity":4}]}`), &addon); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		config        *RunConfiguration
+		wanted        float64
+		wantTaken     float64
+		wantRemaining float64
+	}{
+		{name: "struct literal", config: &RunConfiguration{}, wanted: 2, wantTaken: 0, wantRemaining: 0},
+		{name: "struct literal with an inventory", config: &RunConfiguration{internal_inventory: map[ItemID]uint{2001: 4}}, wanted: 3, wantTaken: 3, wantRemaining: 1},
+		{name: "decoded from JSON", config: decoded, wanted: 2, wantTaken: 0, wantRemaining: 0},
+		{name: "part of the inventory", config: NewRunConfig(&addon, ItemSoftIdentity{}, 0), wanted: 2.5, wantTaken: 2.5, wantRemaining: 1.5},
+		{name: "more than the inventory", config: NewRunConfig(&addon, ItemSoftIdentity{}, 0), wanted: 6, wantTaken: 4, wantRemaining: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.UseInventory(2001, tt.wanted); got != tt.wantTaken {
+				t.Errorf("UseInventory() = %v, want %v", got, tt.wantTaken)
+			}
+			if got := tt.config.InventoryRemaining(2001); got != tt.wantRemaining {
+				t.Errorf("InventoryRemaining() = %v, want %v", got, tt.wantRemaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package shopping_list_export
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+// An in-game addon import string format a shopping list can be exported as
+type ExportFormat = string
+
+const (
+	EXPORT_TSM_GROUP    ExportFormat = "tsm_group"    // TSM group import, a comma separated list of item strings
+	EXPORT_TSM_SHOPPING ExportFormat = "tsm_shopping" // TSM shopping search, exact name searches separated by semicolons
+	EXPORT_AUCTIONATOR  ExportFormat = "auctionator"  // Auctionator shopping list import, with the quantity to buy of each item
+	EXPORT_CPC          ExportFormat = "cpc"          // The bundled CraftingProfitCalculator_data addon's /cpci import
+)
+
+// Every export format, in the order they are offered
+var ExportFormats = []ExportFormat{EXPORT_TSM_GROUP, EXPORT_TSM_SHOPPING, EXPORT_AUCTIONATOR, EXPORT_CPC}
+
+// Version prefix of the CPC addon import string, the addon refuses strings it does not know
+const cpc_import_prefix string = "CPC1"
+
+// Quantities this close over a whole number are float error, not a reason to buy another
+const quantity_rounding_tolerance float64 = 1e-9
+
+/*
+Export a shopping list as an import string for an in-game addon.
+Fractional quantities are rounded up to whole items and entries with nothing to buy are left out.
+TSM strings cannot carry quantities, the other formats can. name titles the list where the format has one.
+*/
+func ExportShoppingList(format ExportFormat, name string, shopping_list []globalTypes.ShoppingList) (string, error) {
+	entries := make([]string, 0, len(shopping_list))
+	for _, li := range shopping_list {
+		quantity := wholeQuantity(li.Quantity)
+		if quantity == 0 {
+			continue
+		}
+		switch format {
+		case EXPORT_TSM_GROUP:
+			entries = append(entries, fmt.Sprintf("i:%d", li.Id))
+		case EXPORT_TSM_SHOPPING:
+			entries = append(entries, fmt.Sprintf("%s/exact", cleanName(li.Name, "/;")))
+		case EXPORT_AUCTIONATOR:
+			entries = append(entries, fmt.Sprintf(`"%s";;0;0;0;0;0;0;0;0;;#;;%d`, cleanName(li.Name, `"^;`), quantity))
+		case EXPORT_CPC:
+			entries = append(entries, fmt.Sprintf("%d:%d:%s", li.Id, quantity, cleanName(li.Name, "^")))
+		default:
+			return "", fmt.Errorf("unknown export format '%s', must be one of %s", format, strings.Join(ExportFormats, ", "))
+		}
+	}
+
+	switch format {
+	case EXPORT_TSM_GROUP:
+		return strings.Join(entries, ","), nil
+	case EXPORT_TSM_SHOPPING:
+		return strings.Join(entries, ";"), nil
+	case EXPORT_AUCTIONATOR:
+		return strings.Join(append([]string{cleanName(name, "^")}, entries...), "^"), nil
+	case EXPORT_CPC:
+		return strings.Join(append([]string{cpc_import_prefix, cleanName(name, "^")}, entries...), "^"), nil
+	default:
+		return "", fmt.Errorf("unknown export format '%s', must be one of %s", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+func wholeQuantity(quantity float64) uint {
+	if quantity <= quantity_rounding_tolerance {
+		return 0
+	}
+	return uint(math.Ceil(quantity - quantity_rounding_tolerance))
+}
+
+// Drop characters a format uses as separators from a name
+func cleanName(name string, separators string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separators, r) {
+			return -1
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,39 @@
+package shopping_list_export
+
+import (
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestExportShoppingList(t *testing.T) {
+	shopping_list := []globalTypes.ShoppingList{
+		{Id: 2001, Name: "Test Herb", Quantity: 5.5},
+		{Id: 2003, Name: "Covered", Quantity: 0},
+		{Id: 2002, Name: `Test "Vial"`, Quantity: 2},
+	}
+	tests := []struct {
+		name    string
+		format  ExportFormat
+		want    string
+		wantErr bool
+	}{
+		{name: "tsm group", format: EXPORT_TSM_GROUP, want: "i:2001,i:2002"},
+		{name: "tsm shopping", format: EXPORT_TSM_SHOPPING, want: `Test Herb/exact;Test "Vial"/exact`},
+		{name: "auctionator", format: EXPORT_AUCTIONATOR, want: `Potions^"Test Herb";;0;0;0;0;0;0;0;0;;#;;6^"Test Vial";;0;0;0;0;0;0;0;0;;#;;2`},
+		{name: "cpc", format: EXPORT_CPC, want: `CPC1^Potions^2001:6:Test Herb^2002:2:Test "Vial"`},
+		{name: "unknown", format: "csv", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExportShoppingList(tt.format, "Potions", shopping_list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportShoppingList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExportShoppingList() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package text_output_helpers
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
@@ -59,26 +60,116 @@ func TextFriendlyOutputFormat(output_data *globalTypes.OutputFormatObject, inden
 	ob.WriteString(fmt.Sprintf("%s (%d) Requires %f", output_data.Name, output_data.Id, output_data.Required))
 	ob.WriteString("\n")
 
+	if output_data.Pruned != "" {
+		ob.WriteString(indentAdder(indent + 1))
+		ob.WriteString(fmt.Sprintf("Not crafted: %s", output_data.Pruned))
+		ob.WriteString("\n")
+	}
+
 	if output_data.Ah.Sales > 0 {
 		ob.WriteString(indentAdder(indent + 1))
 		ob.WriteString(fmt.Sprintf("AH %d: %s/%s/%s/%s", output_data.Ah.Sales, GoldFormatter(output_data.Ah.High), GoldFormatter(output_data.Ah.Low), GoldFormatter(output_data.Ah.Average), GoldFormatter(output_data.Ah.Median)))
+		if output_data.Ah.Commodity {
+			ob.WriteString(" (commodity)")
+		}
+		ob.WriteString("\n")
+		if output_data.Ah.Required > 0 {
+			ob.WriteString(indentAdder(indent + 1))
+			ob.WriteString(fmt.Sprintf("AH fill for %.2f: %s", output_data.Ah.Required, GoldFormatter(output_data.Ah.Fill_cost)))
+			if output_data.Ah.Insufficient_supply {
+				ob.WriteString(fmt.Sprintf(" (insufficient supply, only %d listed)", output_data.Ah.Sales))
+			}
+			ob.WriteString("\n")
+		}
+	}
+	if output_data.Quality_tier > 0 {
+		ob.WriteString(indentAdder(indent + 1))
+		ob.WriteString(fmt.Sprintf("Quality tier %d", output_data.Quality_tier))
+		ob.WriteString("\n")
+	}
+	for _, quality_price := range output_data.Quality_prices {
+		if quality_price.Ah.Sales == 0 {
+			continue
+		}
+		ob.WriteString(indentAdder(indent + 2))
+		ob.WriteString(fmt.Sprintf("Tier %d (%d) AH %d: %s each", quality_price.Tier, quality_price.Id, quality_price.Ah.Sales, GoldFormatter(quality_price.Ah.Price)))
 		ob.WriteString("\n")
 	}
 	if output_data.Vendor > 0 {
 		ob.WriteString(indentAdder(indent + 1))
 		ob.WriteString(fmt.Sprintf("Vendor %s", GoldFormatter(output_data.Vendor)))
+		if output_data.Vendor_source != "" {
+			ob.WriteString(fmt.Sprintf(" (%s)", output_data.Vendor_source))
+		}
+		ob.WriteString("\n")
+	}
+	if output_data.Optimal != nil {
+		ob.WriteString(indentAdder(indent + 1))
+		switch output_data.Optimal.Method {
+		case globalTypes.ACQUIRE_CRAFT:
+			ob.WriteString(fmt.Sprintf("Optimal: craft with (%d) for %s each, %s total", output_data.Optimal.Recipe_id, GoldFormatter(output_data.Optimal.Unit_cost), GoldFormatter(output_data.Optimal.Total_cost)))
+		case globalTypes.ACQUIRE_UNAVAILABLE:
+			ob.WriteString("Optimal: unavailable")
+		default:
+			ob.WriteString(fmt.Sprintf("Optimal: %s for %s each, %s total", output_data.Optimal.Method, GoldFormatter(output_data.Optimal.Unit_cost), GoldFormatter(output_data.Optimal.Total_cost)))
+		}
+		ob.WriteString("\n")
+	}
+	if output_data.Conversion != nil {
+		ob.WriteString(indentAdder(indent + 1))
+		ob.WriteString(fmt.Sprintf("Convert from %s (%d) x%.2f : %s each", output_data.Conversion.Source_name, output_data.Conversion.Source_id, output_data.Conversion.Ratio, GoldFormatter(output_data.Conversion.Unit_cost)))
 		ob.WriteString("\n")
+		for _, step := range output_data.Conversion.Chain {
+			ob.WriteString(indentAdder(indent + 2))
+			ob.WriteString(fmt.Sprintf("%.0f x %s (%d) -> %.0f", step.Takes, step.Name, step.Id, step.Makes))
+			ob.WriteString("\n")
+		}
 	}
 	if len(output_data.Recipes) > 0 {
 		for _, recipe_option := range output_data.Recipes {
 			ob.WriteString(indentAdder(indent + 1))
 			ob.WriteString(fmt.Sprintf("%s - %d - (%d) : %s/%s/%s/%s", recipe_option.Name, recipe_option.Rank, recipe_option.Id, GoldFormatter(recipe_option.High), GoldFormatter(recipe_option.Low), GoldFormatter(recipe_option.Average), GoldFormatter(recipe_option.Median)))
 			ob.WriteString("\n")
+			if recipe_option.Unavailable {
+				ob.WriteString(indentAdder(indent + 2))
+				ob.WriteString("Cannot be crafted, a part can neither be bought nor made")
+				ob.WriteString("\n")
+			} else if recipe_option.Optimal_cost > 0 {
+				ob.WriteString(indentAdder(indent + 2))
+				ob.WriteString(fmt.Sprintf("Optimal parts cost: %s", GoldFormatter(recipe_option.Optimal_cost)))
+				ob.WriteString("\n")
+			}
+			if recipe_option.Expected_unit_cost != recipe_option.Unit_cost {
+				ob.WriteString(indentAdder(indent + 2))
+				ob.WriteString(fmt.Sprintf("Unit cost: %s naive, %s expected (%.2f made per craft, %.1f%% reagents saved)", GoldFormatter(recipe_option.Unit_cost), GoldFormatter(recipe_option.Expected_unit_cost), recipe_option.Output.Expected, recipe_option.Reagent_savings*100))
+				ob.WriteString("\n")
+			}
 			if recipe_option.Ah.Sales > 0 {
 				ob.WriteString(indentAdder(indent + 2))
 				ob.WriteString(fmt.Sprintf("AH %d: %s/%s/%s/%s", recipe_option.Ah.Sales, GoldFormatter(recipe_option.Ah.High), GoldFormatter(recipe_option.Ah.Low), GoldFormatter(recipe_option.Ah.Average), GoldFormatter(recipe_option.Ah.Median)))
 				ob.WriteString("\n")
 			}
+			for _, slot := range recipe_option.Slots {
+				ob.WriteString(indentAdder(indent + 2))
+				ob.WriteString(fmt.Sprintf("Slot %s (%d)", slot.Name, slot.Id))
+				if slot.Required {
+					ob.WriteString(" required")
+				}
+				if slot.Chosen != 0 {
+					ob.WriteString(fmt.Sprintf(": using %d x%d", slot.Chosen, slot.Quantity))
+				} else {
+					ob.WriteString(": empty")
+				}
+				ob.WriteString("\n")
+				for _, option := range slot.Options {
+					if option.Ah.Sales == 0 {
+						continue
+					}
+					ob.WriteString(indentAdder(indent + 3))
+					ob.WriteString(fmt.Sprintf("(%d) AH %d: %s each", option.Id, option.Ah.Sales, GoldFormatter(option.Ah.Price)))
+					ob.WriteString("\n")
+				}
+			}
 			ob.WriteString("\n")
 			if len(recipe_option.Parts) > 0 {
 				for _, opt := range recipe_option.Parts {
@@ -114,23 +205,344 @@ func TextFriendlyOutputFormat(output_data *globalTypes.OutputFormatObject, inden
 			ob.WriteString(fmt.Sprint(rank))
 			ob.WriteString("\n")
 			for _, li := range list {
-				ob.WriteString(indentAdder(indent + 2))
-				ob.WriteString(fmt.Sprintf("[%8.0f] -- %s (%d)", li.Quantity, li.Name, li.Id))
+				ob.WriteString(shoppingListItemFormat(li, indent+2))
+			}
+			ob.WriteString(craftStepsFormat(output_data.Craft_steps[rank], indent+2))
+			ob.WriteString(reagentUseFormat("Used from inventory", output_data.Inventory_used[rank], indent+2))
+		}
+	}
+
+	if len(output_data.Optimal_list) > 0 || len(output_data.Optimal_used) > 0 {
+		ob.WriteString(indentAdder(indent))
+		ob.WriteString("Optimal Shopping List For: ")
+		ob.WriteString(output_data.Name)
+		ob.WriteString("\n")
+		for _, li := range output_data.Optimal_list {
+			ob.WriteString(shoppingListItemFormat(li, indent+1))
+		}
+		ob.WriteString(craftStepsFormat(output_data.Optimal_steps, indent+1))
+		ob.WriteString(reagentUseFormat("Used from inventory", output_data.Optimal_used, indent+1))
+	}
+
+	return ob.String()
+}
+
+/**
+ * Generate a preformatted profit summary for each recipe rank.
+ * @param name The name of the crafted item.
+ * @param profits The profit reports for each rank.
+ */
+func TextFriendlyProfitFormat(name string, profits []globalTypes.ProfitReport) string {
+	if len(profits) == 0 {
+		return ""
+	}
+
+	var ob strings.Builder
+	ob.WriteString("Profit For: ")
+	ob.WriteString(name)
+	ob.WriteString("\n")
+	for _, profit := range profits {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("Rank %d (%d)", profit.Rank, profit.Recipe_id))
+		ob.WriteString("\n")
+		ob.WriteString(indentAdder(2))
+		ob.WriteString(fmt.Sprintf("sale: %s cost: %s ah cut: %s deposit: %s", GoldFormatter(profit.Sale_price), GoldFormatter(profit.Crafting_cost), GoldFormatter(profit.Ah_cut), GoldFormatter(profit.Deposit)))
+		if profit.Naive_cost != profit.Crafting_cost {
+			ob.WriteString(fmt.Sprintf(" (naive cost: %s)", GoldFormatter(profit.Naive_cost)))
+		}
+		ob.WriteString("\n")
+		ob.WriteString(indentAdder(2))
+		ob.WriteString(fmt.Sprintf("net: %s roi: %.1f%% total for %.0f: %s", SignedGoldFormatter(profit.Net_profit), profit.Roi, profit.Quantity, SignedGoldFormatter(profit.Total_net_profit)))
+		ob.WriteString("\n")
+	}
+	return ob.String()
+}
+
+/**
+ * Generate a preformatted report of selling at a chosen price.
+ * @param name The name of the crafted item.
+ * @param reports The what-if for each recipe rank.
+ */
+func TextFriendlyWhatIfFormat(name string, reports []globalTypes.WhatIfReport) string {
+	if len(reports) == 0 {
+		return ""
+	}
+
+	var ob strings.Builder
+	ob.WriteString("What If: ")
+	ob.WriteString(name)
+	ob.WriteString("\n")
+	for _, report := range reports {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("Rank %d (%d) selling at %s", report.Rank, report.Recipe_id, GoldFormatter(report.Sale_price)))
+		ob.WriteString("\n")
+		ob.WriteString(indentAdder(2))
+		ob.WriteString(fmt.Sprintf("cost: %s net: %s %s", GoldFormatter(report.Unit_cost), SignedGoldFormatter(report.Net_profit), WhatIfQuantity(report)))
+		ob.WriteString("\n")
+		for _, reagent := range report.Reagents {
+			ob.WriteString(indentAdder(3))
+			ob.WriteString(fmt.Sprintf("%.0f x %s (%d) at %s, breaks even at %s", reagent.Quantity, reagent.Name, reagent.Id, GoldFormatter(reagent.Unit_cost), GoldFormatter(reagent.Break_even)))
+			ob.WriteString("\n")
+		}
+	}
+	return ob.String()
+}
+
+// Describe how much of a what-if can be crafted profitably
+func WhatIfQuantity(report globalTypes.WhatIfReport) string {
+	if report.Unlimited {
+		return "no supply limit"
+	}
+	return fmt.Sprintf("%d profitable crafts making %.1f", report.Max_crafts, report.Max_quantity)
+}
+
+/**
+ * Generate a preformatted report of what a crafting order costs the crafter.
+ * @param name The name of the crafted item.
+ * @param commission The commission the customer pays.
+ * @param reports The crafting order for each recipe rank.
+ */
+func TextFriendlyCraftingOrderFormat(name string, commission float64, reports []globalTypes.CraftingOrderReport) string {
+	var ob strings.Builder
+	ob.WriteString("Crafting Order For: ")
+	ob.WriteString(name)
+	ob.WriteString(fmt.Sprintf(" commission: %s", GoldFormatter(commission)))
+	ob.WriteString("\n")
+	for _, report := range reports {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("Rank %d (%d) out of pocket: %s net: %s", report.Rank, report.Recipe_id, GoldFormatter(report.Out_of_pocket), SignedGoldFormatter(report.Net_profit)))
+		ob.WriteString("\n")
+		if len(report.Provided) > 0 {
+			ob.WriteString(indentAdder(2))
+			ob.WriteString("Provided by customer:\n")
+			for _, provided := range report.Provided {
+				ob.WriteString(indentAdder(3))
+				ob.WriteString(fmt.Sprintf("[%8.0f] -- %s (%d)", provided.Quantity, provided.Name, provided.Id))
 				ob.WriteString("\n")
-				if li.Cost.Vendor != 0 {
-					ob.WriteString(indentAdder(indent + 10))
-					ob.WriteString("vendor: ")
-					ob.WriteString(GoldFormatter(li.Cost.Vendor))
-					ob.WriteString("\n")
-				}
-				if li.Cost.Ah.Sales != 0 {
-					ob.WriteString(indentAdder(indent + 10))
-					ob.WriteString(fmt.Sprintf("ah: %s/%s/%s/%s", GoldFormatter(li.Cost.Ah.High), GoldFormatter(li.Cost.Ah.Low), GoldFormatter(li.Cost.Ah.Average), GoldFormatter(li.Cost.Ah.Median)))
-					ob.WriteString("\n")
-				}
 			}
 		}
+		if len(report.Shopping_list) > 0 {
+			ob.WriteString(indentAdder(2))
+			ob.WriteString("Bought by crafter:\n")
+			for _, li := range report.Shopping_list {
+				ob.WriteString(shoppingListItemFormat(li, 3))
+			}
+		}
+		ob.WriteString(reagentUseFormat("Cannot Be Bought", report.Unbuyable, 2))
+	}
+	return ob.String()
+}
+
+/**
+ * Generate a preformatted report of how much of an item a budget can craft.
+ * @param name The name of the crafted item.
+ * @param report The budget report, nothing is written when it is nil.
+ */
+func TextFriendlyBudgetFormat(name string, report *globalTypes.BudgetReport) string {
+	if report == nil {
+		return ""
+	}
+
+	var ob strings.Builder
+	ob.WriteString(fmt.Sprintf("Budget of %s For: %s", GoldFormatter(report.Budget), name))
+	ob.WriteString("\n")
+	for _, option := range report.Options {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("Rank %d (%d) %s intermediates: %d for %s", option.Rank, option.Recipe_id, option.Intermediates, option.Quantity, GoldFormatter(option.Cost)))
+		ob.WriteString("\n")
+	}
+
+	best := report.Best
+	ob.WriteString(indentAdder(1))
+	ob.WriteString(fmt.Sprintf("Best: rank %d with %s intermediates crafts %d", best.Rank, best.Intermediates, best.Quantity))
+	ob.WriteString("\n")
+	for _, li := range best.Shopping_list {
+		ob.WriteString(shoppingListItemFormat(li, 2))
+	}
+	ob.WriteString(craftStepsFormat(best.Craft_steps, 2))
+	return ob.String()
+}
+
+/**
+ * Generate a preformatted crafting plan with its merged shopping list.
+ * @param plan The crafting plan.
+ */
+func TextFriendlyPlanFormat(plan *globalTypes.PlanReturn) string {
+	var ob strings.Builder
+	ob.WriteString("Crafting Plan\n")
+	for _, item := range plan.Items {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("[%8d] -- %s (%d) cost: %s", item.Count, item.Item_name, item.Item_id, GoldFormatter(item.Total_cost)))
+		ob.WriteString("\n")
+	}
+
+	ob.WriteString("Shopping List For Plan: ")
+	ob.WriteString(GoldFormatter(plan.Total_cost))
+	ob.WriteString("\n")
+	for _, li := range plan.Shopping_list {
+		ob.WriteString(shoppingListItemFormat(li, 1))
+	}
+	ob.WriteString(reagentUseFormat("Cannot Be Bought", plan.Unbuyable, 1))
+	ob.WriteString(craftStepsFormat(plan.Craft_steps, 1))
+
+	ob.WriteString(reagentUseFormat("Used From Inventory", plan.Used, 0))
+	ob.WriteString(reagentUseFormat("Left In Inventory", plan.Leftover, 0))
+	return ob.String()
+}
+
+/**
+ * Format a titled list of inventory items, nothing is written for an empty list.
+ * Quantities taken toward an average yield can be fractional and keep two decimals.
+ */
+func reagentUseFormat(title string, uses []globalTypes.ReagentUse, indent uint) string {
+	if len(uses) == 0 {
+		return ""
+	}
+
+	var ob strings.Builder
+	ob.WriteString(indentAdder(indent))
+	ob.WriteString(title)
+	ob.WriteString(":\n")
+	for _, use := range uses {
+		ob.WriteString(indentAdder(indent + 1))
+		if use.Quantity == math.Trunc(use.Quantity) {
+			ob.WriteString(fmt.Sprintf("[%8.0f] -- %s (%d)", use.Quantity, use.Name, use.Id))
+		} else {
+			ob.WriteString(fmt.Sprintf("[%8.2f] -- %s (%d)", use.Quantity, use.Name, use.Id))
+		}
+		ob.WriteString("\n")
 	}
+	return ob.String()
+}
+
+/**
+ * Generate a preformatted ranking of the results of a profession scan.
+ * @param results The scan results, already ranked.
+ */
+func TextFriendlyScanFormat(results []globalTypes.CraftScanResult) string {
+	var ob strings.Builder
+	ob.WriteString("Most Profitable Crafts\n")
+	for position, result := range results {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("%3d. %s (%d) rank %d", position+1, result.Item_name, result.Item_id, result.Best.Rank))
+		ob.WriteString("\n")
+		ob.WriteString(indentAdder(3))
+		ob.WriteString(fmt.Sprintf("sale: %s cost: %s net: %s roi: %.1f%%", GoldFormatter(result.Best.Sale_price), GoldFormatter(result.Best.Crafting_cost), SignedGoldFormatter(result.Best.Net_profit), result.Best.Roi))
+		ob.WriteString("\n")
+	}
+	return ob.String()
+}
 
+/**
+ * Generate a preformatted realm arbitrage report.
+ * @param result The arbitrage analysis to format.
+ */
+func TextFriendlyArbitrageFormat(result globalTypes.ArbitrageReturn) string {
+	realm_names := make(map[globalTypes.ConnectedRealmID]string, len(result.Realms))
+
+	var ob strings.Builder
+	ob.WriteString(fmt.Sprintf("Realm Arbitrage For: %s x%.0f", result.Item_name, result.Quantity))
+	ob.WriteString("\n")
+	for _, realm := range result.Realms {
+		realm_names[realm.Id] = realm.Name
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("%s (%d)", realm.Name, realm.Id))
+		if realm.Reagents_available {
+			ob.WriteString(fmt.Sprintf(" reagents: %s", GoldFormatter(realm.Reagent_cost)))
+		} else {
+			ob.WriteString(" reagents: unavailable")
+		}
+		if realm.Sales > 0 {
+			ob.WriteString(fmt.Sprintf(" sells for: %s (%d listed)", GoldFormatter(realm.Sale_price), realm.Sales))
+		} else {
+			ob.WriteString(" sells for: not listed")
+		}
+		ob.WriteString("\n")
+	}
+
+	ob.WriteString("Cheapest Reagents\n")
+	for _, reagent := range result.Reagents {
+		ob.WriteString(indentAdder(1))
+		if reagent.Cheapest_price == 0 {
+			ob.WriteString(fmt.Sprintf("%.0f x %s (%d): unavailable", reagent.Quantity, reagent.Name, reagent.Id))
+		} else if reagent.Cheapest_realm == 0 {
+			ob.WriteString(fmt.Sprintf("%.0f x %s (%d): %s each from a vendor", reagent.Quantity, reagent.Name, reagent.Id, GoldFormatter(reagent.Cheapest_price)))
+		} else {
+			ob.WriteString(fmt.Sprintf("%.0f x %s (%d): %s each on %s", reagent.Quantity, reagent.Name, reagent.Id, GoldFormatter(reagent.Cheapest_price), realm_names[reagent.Cheapest_realm]))
+		}
+		ob.WriteString("\n")
+	}
+
+	ob.WriteString("Profit Matrix (buy -> sell)\n")
+	for _, cell := range result.Matrix {
+		ob.WriteString(indentAdder(1))
+		ob.WriteString(fmt.Sprintf("%s -> %s: %s", realm_names[cell.Buy_realm], realm_names[cell.Sell_realm], SignedGoldFormatter(cell.Net_profit)))
+		ob.WriteString("\n")
+	}
+	if result.Best != nil {
+		ob.WriteString(fmt.Sprintf("Best: buy on %s, sell on %s for %s", realm_names[result.Best.Buy_realm], realm_names[result.Best.Sell_realm], SignedGoldFormatter(result.Best.Net_profit)))
+		ob.WriteString("\n")
+	}
+	if result.Split_cost > 0 {
+		ob.WriteString(fmt.Sprintf("Split: buy each reagent where cheapest for %s, sell on %s for %s", GoldFormatter(result.Split_cost), realm_names[result.Best_sell], SignedGoldFormatter(result.Split_net)))
+		ob.WriteString("\n")
+	}
+	return ob.String()
+}
+
+/**
+ * Format a value that may be negative, such as a loss, into Gold, Silver, and Copper.
+ */
+func SignedGoldFormatter(price_in float64) string {
+	if price_in < 0 {
+		return "-" + GoldFormatter(-price_in)
+	}
+	return GoldFormatter(price_in)
+}
+
+/**
+ * Format a single shopping list entry with its vendor and auction house costs.
+ */
+func shoppingListItemFormat(li globalTypes.ShoppingList, indent uint) string {
+	var ob strings.Builder
+	ob.WriteString(indentAdder(indent))
+	ob.WriteString(fmt.Sprintf("[%8.0f] -- %s (%d)", li.Quantity, li.Name, li.Id))
+	ob.WriteString("\n")
+	if li.Cost.Vendor != 0 {
+		ob.WriteString(indentAdder(indent + 8))
+		ob.WriteString("vendor: ")
+		ob.WriteString(GoldFormatter(li.Cost.Vendor))
+		if li.Cost.Vendor_source != "" {
+			ob.WriteString(fmt.Sprintf(" (%s)", li.Cost.Vendor_source))
+		}
+		ob.WriteString("\n")
+	}
+	if li.Cost.Ah.Sales != 0 {
+		ob.WriteString(indentAdder(indent + 8))
+		ob.WriteString(fmt.Sprintf("ah: %s/%s/%s/%s", GoldFormatter(li.Cost.Ah.High), GoldFormatter(li.Cost.Ah.Low), GoldFormatter(li.Cost.Ah.Average), GoldFormatter(li.Cost.Ah.Median)))
+		if li.Cost.Ah.Insufficient_supply {
+			ob.WriteString(fmt.Sprintf(" (insufficient supply, only %d listed)", li.Cost.Ah.Sales))
+		}
+		ob.WriteString("\n")
+	}
+	return ob.String()
+}
+
+/**
+ * Format the crafts of a shopping list in the order they should be made.
+ */
+func craftStepsFormat(steps []globalTypes.CraftStep, indent uint) string {
+	if len(steps) == 0 {
+		return ""
+	}
+
+	var ob strings.Builder
+	ob.WriteString(indentAdder(indent))
+	ob.WriteString("Craft in order:\n")
+	for position, step := range steps {
+		ob.WriteString(indentAdder(indent + 1))
+		ob.WriteString(fmt.Sprintf("%d. %d x %s (%d) with recipe %d, making %.1f", position+1, step.Crafts, step.Name, step.Id, step.Recipe_id, step.Quantity))
+		ob.WriteString("\n")
+	}
 	return ob.String()
 }
@@ -0,0 +1,245 @@
+package wow_crafting_profits
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/text_output_helpers"
+)
+
+const arbitrage_realm_concurrent int = 4
+
+/*
+Compare buying the reagents for a craft and selling the crafted item across every scanned realm of the region.
+The crafting tree is analysed once on the home realm, then its optimal shopping list is priced on each realm.
+*/
+func (cpc *WoWCpCRunner) arbitrage(ctx context.Context, region string, server globalTypes.RealmName, useAllProfessions bool, professions_input []globalTypes.CharacterProfession, item globalTypes.ItemSoftIdentity, json_config *globalTypes.RunConfiguration, count uint) (globalTypes.ArbitrageReturn, error) {
+	if cpc.History == nil {
+		return globalTypes.ArbitrageReturn{}, errors.New("arbitrage needs auction history to list the scanned realms")
+	}
+
+	encoded_region, err := getRegionCode(region)
+	if err != nil {
+		return globalTypes.ArbitrageReturn{}, err
+	}
+	professions, err := cpc.resolveProfessions(ctx, encoded_region, useAllProfessions, professions_input)
+	if err != nil {
+		return globalTypes.ArbitrageReturn{}, err
+	}
+
+	price_data, err := cpc.performProfitAnalysis(ctx, encoded_region, server, professions, item, count, float64(count), 0, cpc.Auctions, nil)
+	if err != nil {
+		return globalTypes.ArbitrageReturn{}, err
+	}
+	if !price_data.Crafting_status.Craftable {
+		return globalTypes.ArbitrageReturn{}, fmt.Errorf("%s (%d) cannot be crafted with %v", price_data.Item_name, price_data.Item_id, professions)
+	}
+	cpc.analyzeMakeVsBuy(&price_data)
+	intermediate_data := cpc.generateOutputFormat(ctx, price_data, encoded_region)
+	purchases := buildOptimalShoppingList(intermediate_data)
+
+	cpc.loadCommodities(ctx, encoded_region)
+	scan_realms, err := cpc.History.GetScanRealms(ctx)
+	if err != nil {
+		return globalTypes.ArbitrageReturn{}, err
+	}
+
+	reagents := make([]globalTypes.ArbitrageReagent, 0, len(purchases))
+	for _, purchase := range purchases {
+		reagents = append(reagents, globalTypes.ArbitrageReagent{
+			Id:       purchase.Id,
+			Name:     purchase.Name,
+			Quantity: purchase.Quantity,
+			Vendor:   purchase.Cost.Vendor,
+			Prices:   make(map[globalTypes.ConnectedRealmID]float64),
+		})
+	}
+
+	var (
+		realms []globalTypes.ArbitrageRealm
+		mutex  sync.Mutex
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(arbitrage_realm_concurrent)
+	for _, scan_realm := range scan_realms {
+		if !strings.EqualFold(scan_realm.Region, encoded_region) {
+			continue
+		}
+		scan_realm := scan_realm
+		g.Go(func() error {
+			auction_house, err := cpc.Helper.GetRealmAuctions(gCtx, scan_realm.RealmId, encoded_region)
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return err
+				}
+				cpc.Logger.Errorf("Could not fetch auctions for realm %d: %v", scan_realm.RealmId, err)
+				return nil
+			}
+			runner := cpc.withAuctions(&auction_house)
+
+			sale := runner.getAHPrice(gCtx, encoded_region, scan_realm.RealmId, price_data.Item_id, 0, float64(count))
+			realm := globalTypes.ArbitrageRealm{
+				Id:         scan_realm.RealmId,
+				Name:       scan_realm.RealmNames,
+				Sale_price: sale.Low,
+				Sales:      sale.Total_sales,
+			}
+			reagent_prices := make([]globalTypes.AHItemPriceObject, len(reagents))
+			for i, reagent := range reagents {
+				reagent_prices[i] = runner.getAHPrice(gCtx, encoded_region, scan_realm.RealmId, reagent.Id, 0, reagent.Quantity)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			for i, price := range reagent_prices {
+				if price.Total_sales > 0 {
+					reagents[i].Prices[realm.Id] = price.Price
+				}
+			}
+			realms = append(realms, realm)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return globalTypes.ArbitrageReturn{}, err
+	}
+	if len(realms) == 0 {
+		return globalTypes.ArbitrageReturn{}, fmt.Errorf("no scanned realms could be priced in region %s", encoded_region)
+	}
+	slices.SortFunc(realms, func(a, b globalTypes.ArbitrageRealm) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	result := buildArbitrage(realms, reagents, float64(count), estimateDeposit(price_data.Sell_price, json_config.Listing_duration))
+	result.Item_id = price_data.Item_id
+	result.Item_name = price_data.Item_name
+	result.Formatted = text_output_helpers.TextFriendlyArbitrageFormat(result)
+	return result, nil
+}
+
+/*
+Fill in each realm's reagent cost and every buy realm and sell realm pair, along with the split plan
+of buying each reagent wherever it is cheapest and selling where the item fetches the most.
+Reagents that can be bought from a vendor cost the vendor price wherever that is cheaper.
+*/
+func buildArbitrage(realms []globalTypes.ArbitrageRealm, reagents []globalTypes.ArbitrageReagent, quantity float64, deposit float64) globalTypes.ArbitrageReturn {
+	result := globalTypes.ArbitrageReturn{
+		Quantity: quantity,
+		Realms:   realms,
+		Reagents: reagents,
+	}
+
+	for i := range result.Realms {
+		realm := &result.Realms[i]
+		realm.Reagents_available = true
+		realm.Reagent_cost = 0
+		for _, reagent := range result.Reagents {
+			unit, found := arbitrageReagentPrice(reagent, realm.Id)
+			if !found {
+				realm.Reagents_available = false
+				break
+			}
+			realm.Reagent_cost += unit * reagent.Quantity
+		}
+	}
+
+	split_available := true
+	for i := range result.Reagents {
+		reagent := &result.Reagents[i]
+		reagent.Cheapest_price = math.Inf(1)
+		if reagent.Vendor > 0 {
+			reagent.Cheapest_price = reagent.Vendor
+		}
+		for _, realm := range result.Realms {
+			if price, found := reagent.Prices[realm.Id]; found && price < reagent.Cheapest_price {
+				reagent.Cheapest_price = price
+				reagent.Cheapest_realm = realm.Id
+			}
+		}
+		if math.IsInf(reagent.Cheapest_price, 1) {
+			reagent.Cheapest_price = 0
+			split_available = false
+			continue
+		}
+		result.Split_cost += reagent.Cheapest_price * reagent.Quantity
+	}
+
+	best_sale_value := math.Inf(-1)
+	for _, sell := range result.Realms {
+		if sell.Sales == 0 {
+			continue
+		}
+		sale_value := (sell.Sale_price*(1-ah_cut_rate) - deposit) * quantity
+		if sale_value > best_sale_value {
+			best_sale_value = sale_value
+			result.Best_sell = sell.Id
+		}
+		for _, buy := range result.Realms {
+			if !buy.Reagents_available {
+				continue
+			}
+			cell := globalTypes.ArbitrageCell{
+				Buy_realm:    buy.Id,
+				Sell_realm:   sell.Id,
+				Reagent_cost: buy.Reagent_cost,
+				Sale_value:   sale_value,
+				Net_profit:   sale_value - buy.Reagent_cost,
+			}
+			result.Matrix = append(result.Matrix, cell)
+			if result.Best == nil || cell.Net_profit > result.Best.Net_profit {
+				best := cell
+				result.Best = &best
+			}
+		}
+	}
+
+	if split_available && !math.IsInf(best_sale_value, -1) {
+		result.Split_net = best_sale_value - result.Split_cost
+	} else {
+		result.Split_cost = 0
+	}
+
+	return result
+}
+
+// The price of a reagent on a realm, using the vendor when it is cheaper or the only source
+func arbitrageReagentPrice(reagent globalTypes.ArbitrageReagent, realm_id globalTypes.ConnectedRealmID) (float64, bool) {
+	price, found := reagent.Prices[realm_id]
+	if reagent.Vendor > 0 && (!found || reagent.Vendor < price) {
+		return reagent.Vendor, true
+	}
+	return price, found
+}
+
+// Compare buying and selling a craft across every scanned realm of the configured region
+func (cpc *WoWCpCRunner) ArbitrageWithJSONConfig(ctx context.Context, json_config *globalTypes.RunConfiguration) (globalTypes.ArbitrageReturn, error) {
+	runner, ctx, err := cpc.forRun(ctx, json_config)
+	if err != nil {
+		return globalTypes.ArbitrageReturn{}, err
+	}
+	return runner.arbitrage(ctx, json_config.Realm_region, json_config.Realm_name, json_config.UseAllProfessions, json_config.Professions, json_config.Item, json_config, json_config.Item_count)
+}
+
+// Run a realm arbitrage analysis from the command line, saving the results to disk
+func (cpc *WoWCpCRunner) CliArbitrage(ctx context.Context, json_config *globalTypes.RunConfiguration) error {
+	results, err := cpc.ArbitrageWithJSONConfig(ctx, json_config)
+	if err != nil {
+		return err
+	}
+	return saveArbitrageOutput(results, cpc.Logger)
+}
+
+func saveArbitrageOutput(results globalTypes.ArbitrageReturn, logger *cpclog.CpCLog) error {
+	const arbitrage_output_fn string = "arbitrage_output.json"
+	return saveJSONOutput(arbitrage_output_fn, &results, results.Formatted, logger)
+}
@@ -0,0 +1,48 @@
+package wow_crafting_profits
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestBuildArbitrage(t *testing.T) {
+	realms := []globalTypes.ArbitrageRealm{
+		{Id: 1, Name: "Cheap", Sale_price: 1000, Sales: 3},
+		{Id: 2, Name: "Rich", Sale_price: 2000, Sales: 1},
+		{Id: 3, Name: "Empty"},
+	}
+	reagents := []globalTypes.ArbitrageReagent{
+		{Id: 10, Quantity: 2, Prices: map[globalTypes.ConnectedRealmID]float64{1: 100, 2: 300, 3: 50}},
+		{Id: 11, Quantity: 1, Prices: map[globalTypes.ConnectedRealmID]float64{1: 400, 2: 200}},
+		{Id: 12, Quantity: 4, Vendor: 5, Prices: map[globalTypes.ConnectedRealmID]float64{1: 3}},
+	}
+
+	result := buildArbitrage(realms, reagents, 1, 0)
+
+	wantCost := map[globalTypes.ConnectedRealmID]float64{1: 612, 2: 820}
+	for _, realm := range result.Realms {
+		if want, available := wantCost[realm.Id]; realm.Reagents_available != available || realm.Reagent_cost != want && available {
+			t.Errorf("realm %d reagents %v costing %v, want %v costing %v", realm.Id, realm.Reagents_available, realm.Reagent_cost, available, want)
+		}
+	}
+
+	wantCheapest := []globalTypes.ConnectedRealmID{3, 2, 1}
+	for i, reagent := range result.Reagents {
+		if reagent.Cheapest_realm != wantCheapest[i] {
+			t.Errorf("reagent %d cheapest on %d, want %d", reagent.Id, reagent.Cheapest_realm, wantCheapest[i])
+		}
+	}
+
+	// Two buy realms with reagents, two sell realms with listings
+	if len(result.Matrix) != 4 {
+		t.Fatalf("matrix has %d cells, want 4", len(result.Matrix))
+	}
+	if result.Best == nil || result.Best.Buy_realm != 1 || result.Best.Sell_realm != 2 || math.Abs(result.Best.Net_profit-(1900-612)) > 1e-9 {
+		t.Errorf("best = %+v, want buy on 1 and sell on 2 for %v", result.Best, 1900-612)
+	}
+	if result.Best_sell != 2 || math.Abs(result.Split_cost-312) > 1e-9 || math.Abs(result.Split_net-(1900-312)) > 1e-9 {
+		t.Errorf("split cost %v net %v selling on %d, want 312 %v on 2", result.Split_cost, result.Split_net, result.Best_sell, 1900-312)
+	}
+}
@@ -0,0 +1,199 @@
+package wow_crafting_profits
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/util"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/text_output_helpers"
+)
+
+const (
+	default_scan_limit   uint = 25
+	scan_item_concurrent int  = 4
+)
+
+/*
+Create a runner sharing this runner's helpers but with its own auction index,
+so a scan can index a realm without disturbing other runs using the same runner.
+The region's commodity index is shared, commodities are only indexed once however many realms are.
+*/
+func (cpc *WoWCpCRunner) withAuctions(auction_house *BlizzardApi.Auctions) *WoWCpCRunner {
+	runner := *cpc
+	runner.indexAuctions(auction_house)
+	if cpc.memo != nil {
+		// Analyses priced against another auction house cannot be reused
+		runner.memo = newAnalysisMemo()
+	}
+	return &runner
+}
+
+/*
+Scan every recipe the professions know, analysing each crafted item against a single auction house
+snapshot, and rank the results by the net profit of their best recipe rank.
+*/
+func (cpc *WoWCpCRunner) scan(ctx context.Context, region string, server globalTypes.RealmName, useAllProfessions bool, professions_input []globalTypes.CharacterProfession, listing_duration uint, limit uint) (globalTypes.ScanReturn, error) {
+	encoded_region, err := getRegionCode(region)
+	if err != nil {
+		return globalTypes.ScanReturn{}, err
+	}
+
+	professions, err := cpc.resolveProfessions(ctx, encoded_region, useAllProfessions, professions_input)
+	if err != nil {
+		return globalTypes.ScanReturn{}, err
+	}
+
+	crafted_items := util.NewSet[globalTypes.ItemID]()
+	for _, prof := range professions {
+		items, err := cpc.Helper.GetProfessionCraftedItems(ctx, prof, encoded_region, &cpc.staticSources)
+		if err != nil {
+			cpc.Logger.Errorf("Could not list recipes for %s: %v", prof, err)
+			continue
+		}
+		for _, item := range items {
+			crafted_items.Add(item)
+		}
+	}
+	scan_items := crafted_items.ToSlice()
+	slices.Sort(scan_items)
+	cpc.Logger.Infof("Scanning %d crafted items for %v", len(scan_items), professions)
+
+	server_id, err := cpc.Helper.GetConnectedRealmId(ctx, server, encoded_region)
+	if err != nil {
+		return globalTypes.ScanReturn{}, err
+	}
+	auction_house := cpc.Auctions
+	if auction_house == nil {
+		live, err := cpc.Helper.GetRealmAuctions(ctx, server_id, encoded_region)
+		if err != nil {
+			return globalTypes.ScanReturn{}, err
+		}
+		auction_house = &live
+		cpc.loadCommodities(ctx, encoded_region)
+	}
+	cyclic_links, err := cpc.Helper.BuildCyclicRecipeList(ctx, encoded_region, &cpc.staticSources)
+	if err != nil {
+		return globalTypes.ScanReturn{}, err
+	}
+
+	runner := cpc.withAuctions(auction_house)
+
+	var (
+		results []globalTypes.CraftScanResult
+		mutex   sync.Mutex
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(scan_item_concurrent)
+	for _, item_id := range scan_items {
+		item_id := item_id
+		g.Go(func() error {
+			result, found, err := runner.scanItem(gCtx, encoded_region, server, professions, item_id, auction_house, &cyclic_links, listing_duration)
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return err
+				}
+				cpc.Logger.Errorf("Could not scan item %d: %v", item_id, err)
+				return nil
+			}
+			if found {
+				mutex.Lock()
+				results = append(results, result)
+				mutex.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return globalTypes.ScanReturn{}, err
+	}
+
+	rankScanResults(results)
+	if limit == 0 {
+		limit = default_scan_limit
+	}
+	if uint(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return globalTypes.ScanReturn{
+		Results:   results,
+		Scanned:   uint(len(scan_items)),
+		Stats:     runner.memo.stats(),
+		Formatted: text_output_helpers.TextFriendlyScanFormat(results),
+	}, nil
+}
+
+/*
+Analyse a single crafted item for a scan, found is false when the item has no market to sell into.
+*/
+func (cpc *WoWCpCRunner) scanItem(ctx context.Context, region globalTypes.RegionCode, server globalTypes.RealmName, professions []globalTypes.CharacterProfession, item_id globalTypes.ItemID, auction_house *BlizzardApi.Auctions, cyclic_links *globalTypes.SkillTierCyclicLinks, listing_duration uint) (result globalTypes.CraftScanResult, found bool, err error) {
+	price_data, err := cpc.performProfitAnalysis(ctx, region, server, professions, globalTypes.ItemSoftIdentity{ItemId: item_id}, 1, 1, 0, auction_house, cyclic_links)
+	if err != nil {
+		return globalTypes.CraftScanResult{}, false, err
+	}
+	if !price_data.Crafting_status.Craftable {
+		return globalTypes.CraftScanResult{}, false, nil
+	}
+
+	cpc.analyzeMakeVsBuy(&price_data)
+	intermediate_data := cpc.generateOutputFormat(ctx, price_data, region)
+	profits := calculateProfits(price_data, intermediate_data, listing_duration)
+	if len(profits) == 0 {
+		return globalTypes.CraftScanResult{}, false, nil
+	}
+
+	best := profits[0]
+	for _, profit := range profits[1:] {
+		if profit.Net_profit > best.Net_profit {
+			best = profit
+		}
+	}
+
+	return globalTypes.CraftScanResult{
+		Item_id:   item_id,
+		Item_name: price_data.Item_name,
+		Best:      best,
+		Profits:   profits,
+	}, true, nil
+}
+
+// Order scan results from most to least profitable, ties are broken by item id so output is stable
+func rankScanResults(results []globalTypes.CraftScanResult) {
+	slices.SortFunc(results, func(a, b globalTypes.CraftScanResult) int {
+		if c := cmp.Compare(b.Best.Net_profit, a.Best.Net_profit); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Item_id, b.Item_id)
+	})
+}
+
+// Scan every recipe of the configured professions and return the most profitable crafts
+func (cpc *WoWCpCRunner) ScanWithJSONConfig(ctx context.Context, json_config *globalTypes.RunConfiguration, limit uint) (globalTypes.ScanReturn, error) {
+	runner, ctx, err := cpc.forRun(ctx, json_config)
+	if err != nil {
+		return globalTypes.ScanReturn{}, err
+	}
+	return runner.scan(ctx, json_config.Realm_region, json_config.Realm_name, json_config.UseAllProfessions, json_config.Professions, json_config.Listing_duration, limit)
+}
+
+// Run a profession scan from the command line, saving the results to disk
+func (cpc *WoWCpCRunner) CliScan(ctx context.Context, json_config *globalTypes.RunConfiguration, limit uint) error {
+	results, err := cpc.ScanWithJSONConfig(ctx, json_config, limit)
+	if err != nil {
+		return err
+	}
+	return saveScanOutput(results, cpc.Logger)
+}
+
+func saveScanOutput(results globalTypes.ScanReturn, logger *cpclog.CpCLog) error {
+	const scan_output_fn string = "scan_output.json"
+	return saveJSONOutput(scan_output_fn, &results, results.Formatted, logger)
+}
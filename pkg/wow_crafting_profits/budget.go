@@ -0,0 +1,133 @@
+package wow_crafting_profits
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+// Stop searching for more units here, far beyond what any auction house can supply
+const budget_max_units uint = 1 << 20
+
+var budget_policies = []globalTypes.IntermediatePolicy{
+	globalTypes.INTERMEDIATES_MAKE_VS_BUY,
+	globalTypes.INTERMEDIATES_BUY,
+	globalTypes.INTERMEDIATES_CRAFT,
+}
+
+/*
+Work out how many of the item the configured budget can craft, for every recipe rank and intermediate policy.
+Shopping lists are built as for the rank lists, with the inventory on hand applied, and priced by walking
+the auction house listings of each reagent so large quantities pay for the deeper listings.
+Every quantity tried is planned against a copy of the inventory, so the run's own ledger is left as it was.
+Returns nil when the run has no budget.
+*/
+func (cpc *WoWCpCRunner) optimizeBudget(intermediate_data globalTypes.OutputFormatObject, on_hand *globalTypes.RunConfiguration, budget float64) *globalTypes.BudgetReport {
+	if budget <= 0 || len(intermediate_data.Recipes) == 0 {
+		return nil
+	}
+	on_hand = on_hand.InventoryCopy()
+
+	report := globalTypes.BudgetReport{
+		Budget:  budget,
+		Options: make([]globalTypes.BudgetOption, 0, len(intermediate_data.Recipes)*len(budget_policies)),
+	}
+	seen_ranks := make(map[uint]bool)
+	for _, recipe := range intermediate_data.Recipes {
+		if seen_ranks[recipe.Rank] {
+			continue
+		}
+		seen_ranks[recipe.Rank] = true
+		for _, policy := range budget_policies {
+			option := cpc.budgetOption(intermediate_data, recipe.Rank, policy, on_hand, budget)
+			option.Recipe_id = recipe.Id
+			report.Options = append(report.Options, option)
+		}
+	}
+
+	report.Best = slices.MinFunc(report.Options, func(a, b globalTypes.BudgetOption) int {
+		if c := cmp.Compare(b.Quantity, a.Quantity); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Cost, b.Cost)
+	})
+	for i := range report.Options {
+		report.Options[i].Shopping_list = nil
+		report.Options[i].Craft_steps = nil
+	}
+
+	return &report
+}
+
+/*
+Find the most units of the item a budget covers with one rank and intermediate policy.
+The cost only grows with the number of units, so the search doubles until the budget runs out and then bisects.
+Every affordable quantity tried is larger than the last, so the option always holds the largest one.
+No more than budget_max_units are ever tried.
+*/
+func (cpc *WoWCpCRunner) budgetOption(intermediate_data globalTypes.OutputFormatObject, rank uint, policy globalTypes.IntermediatePolicy, on_hand *globalTypes.RunConfiguration, budget float64) globalTypes.BudgetOption {
+	option := globalTypes.BudgetOption{
+		Rank:          rank,
+		Intermediates: policy,
+	}
+
+	affordable := func(units uint) bool {
+		shopping_list, steps, cost, ok := cpc.budgetCost(intermediate_data, rank, policy, on_hand, units)
+		if !ok || cost > budget {
+			return false
+		}
+		option.Quantity, option.Cost, option.Shopping_list, option.Craft_steps = units, cost, shopping_list, steps
+		return true
+	}
+
+	low, high := uint(0), uint(1)
+	for high <= budget_max_units && affordable(high) {
+		low, high = high, min(high*2, budget_max_units+1)
+	}
+	for high-low > 1 {
+		if middle := low + (high-low)/2; affordable(middle) {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return option
+}
+
+// The shopping list, crafts and cost of making units of the item, ok is false when something on the list cannot be bought
+func (cpc *WoWCpCRunner) budgetCost(intermediate_data globalTypes.OutputFormatObject, rank uint, policy globalTypes.IntermediatePolicy, on_hand *globalTypes.RunConfiguration, units uint) ([]globalTypes.ShoppingList, []globalTypes.CraftStep, float64, bool) {
+	intermediate_data.Required = float64(units)
+	on_hand.ResetInventoryLedger()
+	plan := cpc.buildRankPlan(intermediate_data, rank, shoppingPlan{intermediates: policy, on_hand: on_hand})
+	shopping_list := totalShoppingListCosts(plan.shoppingList())
+
+	total := float64(0)
+	for _, li := range shopping_list {
+		cost, ok := cpc.purchaseCost(li)
+		if !ok {
+			return nil, nil, 0, false
+		}
+		total += cost
+	}
+	return shopping_list, plan.craftSteps(), total, true
+}
+
+/*
+The cheaper of buying a shopping list entry from a vendor or from the auction house listings, cheapest first.
+The entry must have been through totalShoppingListCosts, which turns the vendor price into a total.
+ok is false when neither can supply the whole quantity.
+*/
+func (cpc *WoWCpCRunner) purchaseCost(li globalTypes.ShoppingList) (float64, bool) {
+	if li.Quantity <= 0 {
+		return 0, true
+	}
+	cost, found := float64(0), false
+	if li.Cost.Vendor > 0 {
+		cost, found = li.Cost.Vendor, true
+	}
+	if ah_cost, filled := walkOrderBook(auctionPriceListings(cpc.itemAuctions(li.Id)), li.Quantity); filled >= li.Quantity && (!found || ah_cost < cost) {
+		cost, found = ah_cost, true
+	}
+	return cost, found
+}
@@ -0,0 +1,71 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestOfflineBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       float64
+		inventory    string
+		wantQuantity uint
+		wantCost     float64
+		wantHerbs    float64 // Herbs the run's ledger leaves in the bags, the optimal plan for one potion takes 3
+	}{
+		// Each potion takes 3 herbs, 2 listed at 100 and the rest at 200, and a vial at 50
+		{name: "two potions", budget: 1500, wantQuantity: 2, wantCost: 1100},
+		{name: "herbs in the bags", budget: 1500, inventory: `[{"id":2001,"quantity":4}]`, wantQuantity: 3, wantCost: 950, wantHerbs: 1},
+		{name: "too little to craft", budget: 100, wantQuantity: 0, wantCost: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := offlineTestConfig(1001, 1)
+			if tt.inventory != "" {
+				var addon globalTypes.AddonData
+				if err := json.Unmarshal([]byte(`{"inventory":`+tt.inventory+`,"professions":["Alchemy"],"realm":{"realm_name":"Hyjal","region_name":"us"}}`), &addon); err != nil {
+					t.Fatal(err)
+				}
+				config = globalTypes.NewRunConfig(&addon, globalTypes.ItemSoftIdentity{ItemId: 1001}, 1)
+				config.Vendor_prices = offline_vendor_prices
+			}
+			config.Budget = tt.budget
+
+			results, err := offlineTestRunner(t).RunWithJSONConfig(context.Background(), config)
+			if err != nil {
+				t.Fatalf("RunWithJSONConfig() error = %v", err)
+			}
+			if results.Budget == nil {
+				t.Fatal("RunWithJSONConfig() returned no budget report")
+			}
+			if len(results.Budget.Options) != 3 {
+				t.Errorf("RunWithJSONConfig() budget options = %d, want one per intermediate policy", len(results.Budget.Options))
+			}
+			best := results.Budget.Best
+			if best.Quantity != tt.wantQuantity || math.Abs(best.Cost-tt.wantCost) > 0.0001 {
+				t.Errorf("RunWithJSONConfig() budget crafts %d for %v, want %d for %v", best.Quantity, best.Cost, tt.wantQuantity, tt.wantCost)
+			}
+			if herbs := config.InventoryRemaining(2001); herbs != tt.wantHerbs {
+				t.Errorf("RunWithJSONConfig() left %v herbs in the ledger, want %v", herbs, tt.wantHerbs)
+			}
+		})
+	}
+}
+
+func TestBudgetOptionCap(t *testing.T) {
+	cpc := &WoWCpCRunner{}
+	cpc.staticSources.RootDirectory = "../../static_files"
+	// A vendor sold item with no recipes, every quantity is affordable
+	intermediate_data := globalTypes.OutputFormatObject{Id: 2002, Name: "Test Vial", Vendor: 1}
+
+	option := cpc.budgetOption(intermediate_data, 0, globalTypes.INTERMEDIATES_BUY, globalTypes.NewRunConfig(nil, globalTypes.ItemSoftIdentity{}, 0), math.MaxFloat64)
+	if option.Quantity != budget_max_units {
+		t.Errorf("budgetOption() quantity = %d, want the cap of %d", option.Quantity, budget_max_units)
+	}
+}
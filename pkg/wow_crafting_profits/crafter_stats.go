@@ -0,0 +1,46 @@
+package wow_crafting_profits
+
+import (
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+const (
+	default_multicraft_bonus        float64 = 125
+	default_resourcefulness_savings float64 = 30
+)
+
+// Average yield of a craft once multicraft procs are accounted for
+func expectedCraftOutput(base float64, stats globalTypes.CrafterStats) float64 {
+	bonus := stats.Multicraft_bonus
+	if bonus == 0 {
+		bonus = default_multicraft_bonus
+	}
+	return base * (1 + clampPercent(stats.Multicraft)/100*bonus/100)
+}
+
+// Average share of a craft's reagents refunded by resourcefulness
+func expectedReagentSavings(stats globalTypes.CrafterStats) float64 {
+	savings := stats.Resourcefulness_savings
+	if savings == 0 {
+		savings = default_resourcefulness_savings
+	}
+	return clampPercent(stats.Resourcefulness) / 100 * clampPercent(savings) / 100
+}
+
+func clampPercent(value float64) float64 {
+	return min(max(value, 0), 100)
+}
+
+/*
+Cost of each item a craft makes, both naively from the recipe's listed yield and
+as expected once multicraft and resourcefulness are accounted for.
+*/
+func craftUnitCosts(per_craft float64, crafted float64, expected_output float64, reagent_savings float64) (naive float64, expected float64) {
+	if crafted <= 0 {
+		crafted = 1
+	}
+	if expected_output <= 0 {
+		expected_output = crafted
+	}
+	return per_craft / crafted, per_craft * (1 - reagent_savings) / expected_output
+}
@@ -0,0 +1,58 @@
+package wow_crafting_profits
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestCrafterStatsExpectedCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		stats        globalTypes.CrafterStats
+		base         float64
+		wantOutput   float64
+		wantSavings  float64
+		wantNaive    float64
+		wantExpected float64
+	}{
+		{name: "no stats", base: 2, wantOutput: 2, wantNaive: 500, wantExpected: 500},
+		{name: "multicraft", stats: globalTypes.CrafterStats{Multicraft: 20}, base: 2, wantOutput: 2.5, wantNaive: 500, wantExpected: 400},
+		{name: "resourcefulness", stats: globalTypes.CrafterStats{Resourcefulness: 50}, base: 2, wantOutput: 2, wantSavings: 0.15, wantNaive: 500, wantExpected: 425},
+		{name: "custom proc sizes", stats: globalTypes.CrafterStats{Multicraft: 50, Multicraft_bonus: 200, Resourcefulness: 20, Resourcefulness_savings: 50}, base: 1, wantOutput: 2, wantSavings: 0.1, wantNaive: 1000, wantExpected: 450},
+		{name: "out of range stats", stats: globalTypes.CrafterStats{Multicraft: -10, Resourcefulness: 150}, base: 1, wantOutput: 1, wantSavings: 0.3, wantNaive: 1000, wantExpected: 700},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := expectedCraftOutput(tt.base, tt.stats)
+			savings := expectedReagentSavings(tt.stats)
+			if math.Abs(output-tt.wantOutput) > 1e-9 || math.Abs(savings-tt.wantSavings) > 1e-9 {
+				t.Fatalf("expected output %v savings %v, want %v %v", output, savings, tt.wantOutput, tt.wantSavings)
+			}
+			naive, expected := craftUnitCosts(1000, tt.base, output, savings)
+			if math.Abs(naive-tt.wantNaive) > 1e-9 || math.Abs(expected-tt.wantExpected) > 1e-9 {
+				t.Errorf("craftUnitCosts() = %v, %v, want %v, %v", naive, expected, tt.wantNaive, tt.wantExpected)
+			}
+		})
+	}
+}
+
+func TestCalculateProfitsWithCrafterStats(t *testing.T) {
+	intermediate_data := globalTypes.OutputFormatObject{
+		Required: 1,
+		Ah:       globalTypes.OutputFormatPrice{Sales: 10, Low: 10000},
+		Recipes: []globalTypes.OutputFormatRecipe{
+			{Id: 1, Output: globalTypes.OutpoutFormatRecipeOutput{Value: 2, Expected: 2.5}, Reagent_savings: 0.1, Optimal_cost: 8000},
+		},
+	}
+
+	profits := calculateProfits(globalTypes.ProfitAnalysisObject{}, intermediate_data, 24)
+	if len(profits) != 1 {
+		t.Fatalf("calculateProfits() returned %d reports, want 1", len(profits))
+	}
+	if profits[0].Naive_cost != 4000 || math.Abs(profits[0].Crafting_cost-2880) > 1e-9 {
+		t.Errorf("calculateProfits() cost = %v naive %v, want 2880 naive 4000", profits[0].Crafting_cost, profits[0].Naive_cost)
+	}
+}
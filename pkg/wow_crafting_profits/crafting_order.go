@@ -0,0 +1,131 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/text_output_helpers"
+)
+
+/*
+Evaluate crafting an item for a crafting order, where the customer provides some reagents and pays a commission.
+The customer's reagents are treated as inventory, so every rank's shopping list only holds what the crafter pays for.
+The crafter's own bags are not used, reagents from them would otherwise have been sold and are counted at market price.
+*/
+func (cpc *WoWCpCRunner) craftingOrder(ctx context.Context, region string, server globalTypes.RealmName, useAllProfessions bool, professions_input []globalTypes.CharacterProfession, item globalTypes.ItemSoftIdentity, json_config *globalTypes.RunConfiguration, count uint) (globalTypes.CraftingOrderReturn, error) {
+	encoded_region, err := getRegionCode(region)
+	if err != nil {
+		return globalTypes.CraftingOrderReturn{}, err
+	}
+
+	professions, err := cpc.resolveProfessions(ctx, encoded_region, useAllProfessions, professions_input)
+	if err != nil {
+		return globalTypes.CraftingOrderReturn{}, err
+	}
+
+	price_data, err := cpc.performProfitAnalysis(ctx, encoded_region, server, professions, item, count, float64(count), 0, cpc.Auctions, nil)
+	if err != nil {
+		return globalTypes.CraftingOrderReturn{}, err
+	}
+	if !price_data.Crafting_status.Craftable {
+		return globalTypes.CraftingOrderReturn{}, fmt.Errorf("%s (%d) cannot be crafted by %v", price_data.Item_name, price_data.Item_id, professions)
+	}
+	cpc.analyzeMakeVsBuy(&price_data)
+	intermediate_data := cpc.generateOutputFormat(ctx, price_data, encoded_region)
+
+	to_buy, _, provided := cpc.constructShoppingList(intermediate_data, json_config.OrderInventory())
+
+	reports := make([]globalTypes.CraftingOrderReport, 0, len(to_buy))
+	seen_ranks := make(map[uint]bool)
+	for _, recipe := range intermediate_data.Recipes {
+		if seen_ranks[recipe.Rank] {
+			continue
+		}
+		seen_ranks[recipe.Rank] = true
+		report := craftingOrderReport(provided[recipe.Rank], to_buy[recipe.Rank], json_config.Commission)
+		report.Recipe_id = recipe.Id
+		report.Rank = recipe.Rank
+		reports = append(reports, report)
+	}
+
+	return globalTypes.CraftingOrderReturn{
+		Item_id:    price_data.Item_id,
+		Item_name:  price_data.Item_name,
+		Commission: json_config.Commission,
+		Ranks:      reports,
+		Stats:      cpc.memo.stats(),
+		Formatted:  text_output_helpers.TextFriendlyCraftingOrderFormat(price_data.Item_name, json_config.Commission, reports),
+	}, nil
+}
+
+/*
+Cost the shopping list left for a rank once the customer's reagents have been used.
+*/
+func craftingOrderReport(provided []globalTypes.ReagentUse, to_buy []globalTypes.ShoppingList, commission float64) globalTypes.CraftingOrderReport {
+	report := globalTypes.CraftingOrderReport{
+		Provided:      provided,
+		Shopping_list: make([]globalTypes.ShoppingList, 0, len(to_buy)),
+	}
+	for _, li := range to_buy {
+		if li.Quantity > 0 {
+			report.Shopping_list = append(report.Shopping_list, li)
+			if cost, ok := shoppingListItemCost(li); ok {
+				report.Out_of_pocket += cost
+			} else {
+				report.Unbuyable = append(report.Unbuyable, globalTypes.ReagentUse{Id: li.Id, Name: li.Name, Quantity: li.Quantity})
+			}
+		}
+	}
+	report.Net_profit = commission - report.Out_of_pocket
+	return report
+}
+
+/*
+The cheaper of buying a shopping list entry from a vendor or at the run's auction house price.
+Entries must have been through totalShoppingListCosts, which turns the vendor price into a total.
+ok is false when the entry can be bought nowhere.
+*/
+func shoppingListItemCost(li globalTypes.ShoppingList) (float64, bool) {
+	cost, found := float64(0), false
+	if li.Cost.Vendor > 0 {
+		cost, found = li.Cost.Vendor, true
+	}
+	if li.Cost.Ah.Sales > 0 {
+		ah_cost := li.Cost.Ah.Price * li.Quantity
+		if ah_cost == 0 {
+			ah_cost = li.Cost.Ah.Median
+		}
+		if !found || ah_cost < cost {
+			cost, found = ah_cost, true
+		}
+	}
+	return cost, found
+}
+
+// Evaluate a crafting order for the configured item, using the configuration's order reagents and commission
+func (cpc *WoWCpCRunner) CraftingOrderWithJSONConfig(ctx context.Context, json_config *globalTypes.RunConfiguration) (globalTypes.CraftingOrderReturn, error) {
+	runner, ctx, err := cpc.forRun(ctx, json_config)
+	if err != nil {
+		return globalTypes.CraftingOrderReturn{}, err
+	}
+	return runner.craftingOrder(ctx, json_config.Realm_region, json_config.Realm_name, json_config.UseAllProfessions, json_config.Professions, json_config.Item, json_config, json_config.Item_count)
+}
+
+// Evaluate a crafting order from the command line, saving the results to disk
+func (cpc *WoWCpCRunner) CliCraftingOrder(ctx context.Context, json_config *globalTypes.RunConfiguration) error {
+	results, err := cpc.CraftingOrderWithJSONConfig(ctx, json_config)
+	if err != nil {
+		return err
+	}
+	for _, report := range results.Ranks {
+		cpc.Logger.Infof("Rank %d (%d): out of pocket %s, net %s", report.Rank, report.Recipe_id, text_output_helpers.GoldFormatter(report.Out_of_pocket), text_output_helpers.SignedGoldFormatter(report.Net_profit))
+	}
+	return saveCraftingOrderOutput(results, cpc.Logger)
+}
+
+func saveCraftingOrderOutput(results globalTypes.CraftingOrderReturn, logger *cpclog.CpCLog) error {
+	const order_output_fn string = "order_output.json"
+	return saveJSONOutput(order_output_fn, &results, results.Formatted, logger)
+}
@@ -0,0 +1,67 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestOfflineCraftingOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		provided     map[globalTypes.ItemID]uint
+		wantProvided float64
+		wantCost     float64
+		wantProfit   float64
+	}{
+		// 6 herbs for 1000 and 2 vials for 100
+		{name: "nothing provided", wantCost: 1100, wantProfit: -100},
+		{name: "herbs provided", provided: map[globalTypes.ItemID]uint{2001: 6}, wantProvided: 6, wantCost: 100, wantProfit: 900},
+		{name: "some herbs provided", provided: map[globalTypes.ItemID]uint{2001: 3}, wantProvided: 3, wantCost: 600, wantProfit: 400},
+		{name: "more than needed provided", provided: map[globalTypes.ItemID]uint{2001: 10}, wantProvided: 6, wantCost: 100, wantProfit: 900},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := offlineTestConfig(1001, 2)
+			config.Order_reagents = tt.provided
+			config.Commission = 1000
+			results, err := offlineTestRunner(t).CraftingOrderWithJSONConfig(context.Background(), config)
+			if err != nil {
+				t.Fatalf("CraftingOrderWithJSONConfig() error = %v", err)
+			}
+			if len(results.Ranks) != 1 {
+				t.Fatalf("CraftingOrderWithJSONConfig() ranks = %+v, want one rank", results.Ranks)
+			}
+			report := results.Ranks[0]
+			provided := float64(0)
+			for _, use := range report.Provided {
+				provided += use.Quantity
+			}
+			if provided != tt.wantProvided {
+				t.Errorf("CraftingOrderWithJSONConfig() used %v provided reagents, want %v", provided, tt.wantProvided)
+			}
+			if math.Abs(report.Out_of_pocket-tt.wantCost) > 0.0001 || math.Abs(report.Net_profit-tt.wantProfit) > 0.0001 {
+				t.Errorf("CraftingOrderWithJSONConfig() cost %v profit %v, want %v %v", report.Out_of_pocket, report.Net_profit, tt.wantCost, tt.wantProfit)
+			}
+		})
+	}
+}
+
+func TestCraftingOrderReportUnbuyable(t *testing.T) {
+	to_buy := []globalTypes.ShoppingList{
+		{Id: 2001, Name: "Test Herb", Quantity: 2, Cost: globalTypes.ShoppingListCost{Ah: globalTypes.OutputFormatPrice{Sales: 10, Price: 150}}},
+		{Id: 2002, Name: "Test Vial", Quantity: 1, Cost: globalTypes.ShoppingListCost{Vendor: 50}},
+		{Id: 2003, Name: "Test Spark", Quantity: 1},
+	}
+
+	report := craftingOrderReport(nil, to_buy, 1000)
+	if report.Out_of_pocket != 350 || report.Net_profit != 650 {
+		t.Errorf("craftingOrderReport() cost %v profit %v, want 350 650", report.Out_of_pocket, report.Net_profit)
+	}
+	if len(report.Unbuyable) != 1 || report.Unbuyable[0].Id != 2003 {
+		t.Errorf("craftingOrderReport() unbuyable = %+v, want only Test Spark", report.Unbuyable)
+	}
+}
@@ -0,0 +1,130 @@
+package wow_crafting_profits
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/text_output_helpers"
+)
+
+/*
+Plan crafting several items at once, each following its make vs. buy analysis.
+Every item is planned against the same inventory ledger, so reagents and intermediates in the bags
+are shared across the whole plan rather than counted once per item.
+*/
+func (cpc *WoWCpCRunner) craftingPlan(ctx context.Context, region string, server globalTypes.RealmName, useAllProfessions bool, professions_input []globalTypes.CharacterProfession, plan_items []globalTypes.PlanItem, on_hand *globalTypes.RunConfiguration) (globalTypes.PlanReturn, error) {
+	if len(plan_items) == 0 {
+		return globalTypes.PlanReturn{}, errors.New("a crafting plan needs at least one item")
+	}
+
+	encoded_region, err := getRegionCode(region)
+	if err != nil {
+		return globalTypes.PlanReturn{}, err
+	}
+
+	professions, err := cpc.resolveProfessions(ctx, encoded_region, useAllProfessions, professions_input)
+	if err != nil {
+		return globalTypes.PlanReturn{}, err
+	}
+
+	on_hand.ResetInventoryLedger()
+	plan := shoppingPlan{on_hand: on_hand}
+	items := make([]globalTypes.PlanItemResult, 0, len(plan_items))
+	for _, plan_item := range plan_items {
+		price_data, err := cpc.performProfitAnalysis(ctx, encoded_region, server, professions, plan_item.Item, plan_item.Count, float64(plan_item.Count), 0, cpc.Auctions, nil)
+		if err != nil {
+			return globalTypes.PlanReturn{}, err
+		}
+		cpc.analyzeMakeVsBuy(&price_data)
+		intermediate_data := cpc.generateOutputFormat(ctx, price_data, encoded_region)
+
+		plan = buildOptimalPlan(intermediate_data, plan)
+
+		result := globalTypes.PlanItemResult{
+			Item_id:   price_data.Item_id,
+			Item_name: price_data.Item_name,
+			Count:     plan_item.Count,
+		}
+		if price_data.Acquisition != nil {
+			result.Unit_cost = price_data.Acquisition.Unit_cost
+			result.Total_cost = price_data.Acquisition.Total_cost
+		}
+		items = append(items, result)
+	}
+
+	to_buy := totalShoppingListCosts(plan.shoppingList())
+	used := plan.inventoryUsed()
+
+	plan_return := globalTypes.PlanReturn{
+		Items:         items,
+		Shopping_list: make([]globalTypes.ShoppingList, 0, len(to_buy)),
+		Craft_steps:   plan.craftSteps(),
+		Used:          used,
+		Leftover:      leftoverInventory(on_hand, used),
+		Stats:         cpc.memo.stats(),
+	}
+	for _, li := range to_buy {
+		if li.Quantity > 0 {
+			plan_return.Shopping_list = append(plan_return.Shopping_list, li)
+			if cost, ok := shoppingListItemCost(li); ok {
+				plan_return.Total_cost += cost
+			} else {
+				plan_return.Unbuyable = append(plan_return.Unbuyable, globalTypes.ReagentUse{Id: li.Id, Name: li.Name, Quantity: li.Quantity})
+			}
+		}
+	}
+	plan_return.Formatted = text_output_helpers.TextFriendlyPlanFormat(&plan_return)
+
+	return plan_return, nil
+}
+
+// Everything still in the inventory, named when the plan used some of the item
+func leftoverInventory(on_hand *globalTypes.RunConfiguration, used []globalTypes.ReagentUse) []globalTypes.ReagentUse {
+	names := make(map[globalTypes.ItemID]globalTypes.ItemName, len(used))
+	for _, use := range used {
+		names[use.Id] = use.Name
+	}
+
+	leftover := make([]globalTypes.ReagentUse, 0)
+	for item_id, quantity := range on_hand.RemainingInventory() {
+		leftover = append(leftover, globalTypes.ReagentUse{Id: item_id, Name: names[item_id], Quantity: quantity})
+	}
+	slices.SortFunc(leftover, func(a, b globalTypes.ReagentUse) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return leftover
+}
+
+// Plan crafting every item of the configuration's plan, sharing its inventory
+func (cpc *WoWCpCRunner) PlanWithJSONConfig(ctx context.Context, json_config *globalTypes.RunConfiguration) (globalTypes.PlanReturn, error) {
+	runner, ctx, err := cpc.forRun(ctx, json_config)
+	if err != nil {
+		return globalTypes.PlanReturn{}, err
+	}
+	return runner.craftingPlan(ctx, json_config.Realm_region, json_config.Realm_name, json_config.UseAllProfessions, json_config.Professions, json_config.Plan_items, json_config)
+}
+
+// Plan crafting several items from the command line, saving the results to disk
+func (cpc *WoWCpCRunner) CliPlan(ctx context.Context, json_config *globalTypes.RunConfiguration) error {
+	results, err := cpc.PlanWithJSONConfig(ctx, json_config)
+	if err != nil {
+		return err
+	}
+	for _, item := range results.Items {
+		cpc.Logger.Infof("%d x %s (%d): %s", item.Count, item.Item_name, item.Item_id, text_output_helpers.GoldFormatter(item.Total_cost))
+	}
+	cpc.Logger.Infof("Shopping list for the plan costs %s", text_output_helpers.GoldFormatter(results.Total_cost))
+	return errors.Join(
+		savePlanOutput(results, cpc.Logger),
+		saveShoppingListExport(json_config.Export_format, "CPC Plan", results.Shopping_list, cpc.Logger),
+	)
+}
+
+func savePlanOutput(results globalTypes.PlanReturn, logger *cpclog.CpCLog) error {
+	const plan_output_fn string = "plan_output.json"
+	return saveJSONOutput(plan_output_fn, &results, results.Formatted, logger)
+}
@@ -0,0 +1,54 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestOfflineCraftingPlan(t *testing.T) {
+	var addon globalTypes.AddonData
+	addon_json := `{"inventory":[{"id":2001,"quantity":8},{"id":2002,"quantity":5}],"professions":["Alchemy"],"realm":{"realm_name":"Hyjal","region_name":"us"}}`
+	if err := json.Unmarshal([]byte(addon_json), &addon); err != nil {
+		t.Fatal(err)
+	}
+	config := globalTypes.NewRunConfig(&addon, globalTypes.ItemSoftIdentity{}, 0)
+	config.Vendor_prices = offline_vendor_prices
+	// 2 potions need 6 herbs and 2 vials, and 4 more herbs are wanted on their own
+	config.Plan_items = globalTypes.NewPlanItems(map[string]uint{"1001": 2, "2001": 4})
+
+	results, err := offlineTestRunner(t).PlanWithJSONConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("PlanWithJSONConfig() error = %v", err)
+	}
+
+	if len(results.Items) != 2 {
+		t.Fatalf("PlanWithJSONConfig() items = %+v, want 2", results.Items)
+	}
+	if len(results.Shopping_list) != 1 || results.Shopping_list[0].Id != 2001 || results.Shopping_list[0].Quantity != 2 {
+		t.Errorf("PlanWithJSONConfig() shopping list = %+v, want 2 of item 2001", results.Shopping_list)
+	}
+	wantUsed := []globalTypes.ReagentUse{{Id: 2001, Name: "Test Herb", Quantity: 8}, {Id: 2002, Name: "Test Vial", Quantity: 2}}
+	if !reflect.DeepEqual(results.Used, wantUsed) {
+		t.Errorf("PlanWithJSONConfig() used = %+v, want %+v", results.Used, wantUsed)
+	}
+	wantLeftover := []globalTypes.ReagentUse{{Id: 2002, Name: "Test Vial", Quantity: 3}}
+	if !reflect.DeepEqual(results.Leftover, wantLeftover) {
+		t.Errorf("PlanWithJSONConfig() leftover = %+v, want %+v", results.Leftover, wantLeftover)
+	}
+	if len(results.Craft_steps) != 1 || results.Craft_steps[0].Id != 1001 || results.Craft_steps[0].Crafts != 2 {
+		t.Errorf("PlanWithJSONConfig() craft steps = %+v, want 2 crafts of item 1001", results.Craft_steps)
+	}
+	if results.Total_cost <= 0 {
+		t.Errorf("PlanWithJSONConfig() total cost = %v, want the cost of the herbs", results.Total_cost)
+	}
+}
+
+func TestCraftingPlanNeedsItems(t *testing.T) {
+	if _, err := offlineTestRunner(t).PlanWithJSONConfig(context.Background(), offlineTestConfig(1001, 1)); err == nil {
+		t.Error("PlanWithJSONConfig() with no plan items succeeded, want an error")
+	}
+}
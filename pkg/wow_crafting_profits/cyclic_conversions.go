@@ -0,0 +1,196 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"math"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+// The largest number of items considered when walking a single conversion cycle
+const max_cyclic_component_size int = 32
+
+type cyclicLink = struct {
+	Id    uint
+	Takes float64
+	Makes float64
+}
+
+/*
+Units of the linked item required to produce one unit of the item that owns the link.
+*/
+func cyclicLinkRatio(link cyclicLink) float64 {
+	if link.Makes <= 0 {
+		return link.Takes
+	}
+	return link.Takes / link.Makes
+}
+
+/*
+The cheapest price a single unit can be bought at, vendors are preferred when they sell the item.
+Items that cannot be bought are given a value of +Inf.
+*/
+func cheapestUnitPrice(ah globalTypes.AHItemPriceObject, vendor_price float64) float64 {
+	if vendor_price > 0 {
+		return vendor_price
+	}
+	if ah.Total_sales > 0 {
+		return ah.Price
+	}
+	return math.Inf(1)
+}
+
+/*
+Collect every item reachable from item_id through the cyclic link table.
+*/
+func cyclicComponent(item_id globalTypes.ItemID, links globalTypes.SkillTierCyclicLinks) []globalTypes.ItemID {
+	component := []globalTypes.ItemID{item_id}
+	seen := map[globalTypes.ItemID]bool{item_id: true}
+	for i := 0; i < len(component) && len(component) < max_cyclic_component_size; i++ {
+		for _, link := range links[component[i]] {
+			if !seen[link.Id] {
+				seen[link.Id] = true
+				component = append(component, link.Id)
+			}
+		}
+	}
+	return component
+}
+
+/*
+Find the cheapest way to get one unit of target given the direct purchase price of every item in its cycle.
+The returned chain is empty when buying target directly is cheapest, otherwise it runs from the item
+converted into target back to the item that should be purchased.
+*/
+func solveCyclicConversion(target globalTypes.ItemID, links globalTypes.SkillTierCyclicLinks, direct map[globalTypes.ItemID]float64) (float64, []cyclicLink) {
+	best := make(map[globalTypes.ItemID]float64, len(direct))
+	via := make(map[globalTypes.ItemID]cyclicLink)
+	for id, price := range direct {
+		best[id] = price
+	}
+
+	// Bellman-Ford style relaxation, costs multiply along the chain so a path never needs more hops than items
+	for range len(direct) {
+		changed := false
+		for id := range direct {
+			for _, link := range links[id] {
+				source_cost, present := best[link.Id]
+				if !present || math.IsInf(source_cost, 1) {
+					continue
+				}
+				if cost := cyclicLinkRatio(link) * source_cost; cost < best[id] {
+					best[id] = cost
+					via[id] = link
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var chain []cyclicLink
+	visited := map[globalTypes.ItemID]bool{target: true}
+	current := target
+	for {
+		link, present := via[current]
+		if !present || visited[link.Id] {
+			break
+		}
+		chain = append(chain, link)
+		visited[link.Id] = true
+		current = link.Id
+	}
+
+	if len(chain) == 0 {
+		return direct[target], nil
+	}
+
+	// Recompute from the chain itself so the cost always matches what will be bought
+	cost := direct[current]
+	for _, link := range chain {
+		cost *= cyclicLinkRatio(link)
+	}
+	if cost >= direct[target] {
+		return direct[target], nil
+	}
+	return cost, chain
+}
+
+/*
+Analyze an item that is part of a recipe cycle (transmutes, crushing, conversions).
+Rather than recursing into recipes that would loop back on themselves the item is priced
+by the cheapest purchase anywhere in its cycle, scaled by the Takes/Makes ratio of each conversion.
+*/
+func (cpc *WoWCpCRunner) performCyclicAnalysis(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, item_id globalTypes.ItemID, quantity uint, required float64, links globalTypes.SkillTierCyclicLinks) (globalTypes.ProfitAnalysisObject, error) {
+	item_detail, err := cpc.Helper.GetItemDetails(ctx, item_id, region)
+	if err != nil {
+		return globalTypes.ProfitAnalysisObject{}, err
+	}
+
+	component := cyclicComponent(item_id, links)
+	ah_prices := make(map[globalTypes.ItemID]globalTypes.AHItemPriceObject, len(component))
+	vendor_prices := make(map[globalTypes.ItemID]float64, len(component))
+	direct := make(map[globalTypes.ItemID]float64, len(component))
+	for _, id := range component {
+		vendor_price, _, err := cpc.findNoneAHPrice(ctx, id, region)
+		if err != nil {
+			return globalTypes.ProfitAnalysisObject{}, err
+		}
+		ah_prices[id] = cpc.getAHPrice(ctx, region, realm_id, id, 0, required)
+		vendor_prices[id] = vendor_price
+		direct[id] = cheapestUnitPrice(ah_prices[id], vendor_price)
+	}
+
+	price_obj := globalTypes.ProfitAnalysisObject{
+		Item_id:       item_id,
+		Item_name:     item_detail.Name,
+		Item_quantity: float64(quantity),
+		Ah_price:      ah_prices[item_id],
+		Vendor_price:  vendor_prices[item_id],
+	}
+
+	unit_cost, chain := solveCyclicConversion(item_id, links, direct)
+	if math.IsInf(unit_cost, 1) {
+		// Nothing in the cycle is sold, so the item has no price and none of it can be supplied
+		cpc.Logger.Infof("Cyclic item %s (%d) cannot be bought or converted from anything that can", item_detail.Name, item_id)
+		price_obj.Ah_price.Required = required
+		price_obj.Ah_price.Insufficient_supply = true
+		price_obj.Unpriced = true
+		return price_obj, nil
+	}
+	if len(chain) == 0 {
+		cpc.Logger.Debugf("Cyclic item %s (%d) is cheapest to buy directly", item_detail.Name, item_id)
+		return price_obj, nil
+	}
+
+	conversion := globalTypes.CyclicConversion{
+		Ratio:     1,
+		Unit_cost: unit_cost,
+		Chain:     make([]globalTypes.CyclicConversionStep, 0, len(chain)),
+	}
+	for _, link := range chain {
+		step_detail, err := cpc.Helper.GetItemDetails(ctx, link.Id, region)
+		if err != nil {
+			return globalTypes.ProfitAnalysisObject{}, err
+		}
+		conversion.Ratio *= cyclicLinkRatio(link)
+		conversion.Chain = append(conversion.Chain, globalTypes.CyclicConversionStep{
+			Id:    link.Id,
+			Name:  step_detail.Name,
+			Takes: link.Takes,
+			Makes: link.Makes,
+		})
+	}
+	source := conversion.Chain[len(conversion.Chain)-1]
+	conversion.Source_id = source.Id
+	conversion.Source_name = source.Name
+	conversion.Source_ah = ah_prices[source.Id]
+	conversion.Source_vendor = vendor_prices[source.Id]
+
+	cpc.Logger.Infof("Cyclic item %s (%d) is cheapest converted from %s (%d) at %f per unit", item_detail.Name, item_id, source.Name, source.Id, conversion.Ratio)
+	price_obj.Cyclic_conversion = &conversion
+
+	return price_obj, nil
+}
@@ -0,0 +1,114 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestSolveCyclicConversion(t *testing.T) {
+	// 10 lesser (1) make 1 greater (2), 1 greater makes 10 lesser, 1 greater makes 1 other (3) and back
+	links := globalTypes.SkillTierCyclicLinks{
+		1: {{Id: 2, Takes: 1, Makes: 10}},
+		2: {{Id: 1, Takes: 10, Makes: 1}, {Id: 3, Takes: 1, Makes: 1}},
+		3: {{Id: 2, Takes: 1, Makes: 1}},
+	}
+
+	tests := []struct {
+		name       string
+		target     globalTypes.ItemID
+		direct     map[globalTypes.ItemID]float64
+		wantCost   float64
+		wantSource []globalTypes.ItemID
+	}{
+		{
+			name:       "buying directly is cheapest",
+			target:     2,
+			direct:     map[globalTypes.ItemID]float64{1: 100, 2: 500, 3: 900},
+			wantCost:   500,
+			wantSource: nil,
+		},
+		{
+			name:       "single conversion is cheaper",
+			target:     2,
+			direct:     map[globalTypes.ItemID]float64{1: 10, 2: 500, 3: 900},
+			wantCost:   100,
+			wantSource: []globalTypes.ItemID{1},
+		},
+		{
+			name:       "multi step conversion is cheaper",
+			target:     3,
+			direct:     map[globalTypes.ItemID]float64{1: 10, 2: 500, 3: 900},
+			wantCost:   100,
+			wantSource: []globalTypes.ItemID{2, 1},
+		},
+		{
+			name:       "target cannot be bought",
+			target:     1,
+			direct:     map[globalTypes.ItemID]float64{1: math.Inf(1), 2: 500, 3: 900},
+			wantCost:   50,
+			wantSource: []globalTypes.ItemID{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, chain := solveCyclicConversion(tt.target, links, tt.direct)
+			if math.Abs(cost-tt.wantCost) > 0.0001 {
+				t.Errorf("solveCyclicConversion() cost = %v, want %v", cost, tt.wantCost)
+			}
+			if len(chain) != len(tt.wantSource) {
+				t.Fatalf("solveCyclicConversion() chain = %v, want %v", chain, tt.wantSource)
+			}
+			for i, link := range chain {
+				if link.Id != tt.wantSource[i] {
+					t.Errorf("solveCyclicConversion() chain[%d] = %d, want %d", i, link.Id, tt.wantSource[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCyclicComponent(t *testing.T) {
+	links := globalTypes.SkillTierCyclicLinks{
+		1: {{Id: 2, Takes: 1, Makes: 1}},
+		2: {{Id: 1, Takes: 1, Makes: 1}, {Id: 3, Takes: 1, Makes: 1}},
+		3: {{Id: 2, Takes: 1, Makes: 1}},
+		4: {{Id: 5, Takes: 1, Makes: 1}},
+	}
+	got := cyclicComponent(1, links)
+	if len(got) != 3 {
+		t.Errorf("cyclicComponent() = %v, want 3 items", got)
+	}
+}
+
+func TestPerformCyclicAnalysisUnpriced(t *testing.T) {
+	// Neither Test Tonic nor Test Dust is listed or sold by vendors
+	links := globalTypes.SkillTierCyclicLinks{
+		1002: {{Id: 2003, Takes: 2, Makes: 1}},
+		2003: {{Id: 1002, Takes: 1, Makes: 2}},
+	}
+	cpc := offlineTestRunner(t)
+
+	analysis, err := cpc.performCyclicAnalysis(context.Background(), "us", 57, 2003, 2, 4, links)
+	if err != nil {
+		t.Fatalf("performCyclicAnalysis() error = %v", err)
+	}
+	if analysis.Cyclic_conversion != nil || !analysis.Unpriced || !analysis.Ah_price.Insufficient_supply || analysis.Ah_price.Required != 4 {
+		t.Errorf("performCyclicAnalysis() = %+v, want an unpriced item short of 4 units", analysis)
+	}
+
+	cost := cpc.recipeCostCalculator(globalTypes.RecipeOption{Prices: []globalTypes.ProfitAnalysisObject{analysis}})
+	if cost.Low != 0 || cost.High != 0 || cost.Price != 0 {
+		t.Errorf("recipeCostCalculator() = %+v, want the unpriced part to add nothing", cost)
+	}
+
+	// Parts outside a cycle with no listings still make the recipe unaffordable
+	unlisted := globalTypes.ProfitAnalysisObject{Item_id: 2003, Item_quantity: 1}
+	cost = cpc.recipeCostCalculator(globalTypes.RecipeOption{Prices: []globalTypes.ProfitAnalysisObject{analysis, unlisted}})
+	if cost.Low != math.MaxUint64 {
+		t.Errorf("recipeCostCalculator() low = %v, want an unlisted part to cost %v", cost.Low, float64(math.MaxUint64))
+	}
+}
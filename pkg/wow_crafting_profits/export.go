@@ -0,0 +1,42 @@
+package wow_crafting_profits
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/shopping_list_export"
+)
+
+// Save a shopping list as an in-game addon import string, does nothing when no format is configured
+func saveShoppingListExport(format shopping_list_export.ExportFormat, name string, shopping_list []globalTypes.ShoppingList, logger *cpclog.CpCLog) error {
+	const export_output_fn string = "shopping_list_export"
+
+	if format == "" {
+		return nil
+	}
+
+	exported, err := shopping_list_export.ExportShoppingList(format, name, shopping_list)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Saving %s shopping list export", format)
+	if err := func() error {
+		exportFile, err := os.Create(export_output_fn)
+		if err != nil {
+			return err
+		}
+		defer exportFile.Close()
+		export_writer := bufio.NewWriter(exportFile)
+		if _, err := export_writer.WriteString(exported + "\n"); err != nil {
+			return err
+		}
+		return export_writer.Flush()
+	}(); err != nil {
+		return fmt.Errorf("error saving shopping list export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package wow_crafting_profits
+
+import (
+	"math"
+	"slices"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+/*
+Walk a completed profit analysis and mark every node with the cheapest way to acquire it,
+either buying it, converting it through a recipe cycle, or crafting it with the best recipe.
+*/
+func (cpc *WoWCpCRunner) analyzeMakeVsBuy(price_data *globalTypes.ProfitAnalysisObject) {
+	exclusions := cpc.staticSources.GetShoppingRecipeExclusionList().Exclusions
+	decideAcquisition(price_data, exclusions)
+}
+
+/*
+Decide how a single node should be acquired, recursing into every recipe option so that each part
+carries its own decision. The cheapest recipe is recorded whenever the item can be crafted, even if
+buying it wins. Returns the cost of a single unit, or +Inf if the item cannot be acquired.
+*/
+func decideAcquisition(node *globalTypes.ProfitAnalysisObject, exclusions []uint) float64 {
+	best := math.Inf(1)
+	method := globalTypes.ACQUIRE_UNAVAILABLE
+	best_craft := math.Inf(1)
+	var recipe_id uint
+
+	if node.Cyclic_conversion != nil && node.Cyclic_conversion.Unit_cost < best {
+		best = node.Cyclic_conversion.Unit_cost
+		method = globalTypes.ACQUIRE_CONVERT
+	}
+	if node.Vendor_price > 0 && node.Vendor_price < best {
+		best = node.Vendor_price
+		method = globalTypes.ACQUIRE_BUY_VENDOR
+	}
+	if node.Ah_price.Total_sales > 0 && node.Ah_price.Price < best {
+		best = node.Ah_price.Price
+		method = globalTypes.ACQUIRE_BUY_AH
+	}
+
+	for i := range node.Recipe_options {
+		option := &node.Recipe_options[i]
+		per_craft := float64(0)
+		for j := range option.Prices {
+			part := &option.Prices[j]
+			per_craft += decideAcquisition(part, exclusions) * part.Item_quantity
+		}
+		if math.IsInf(per_craft, 1) {
+			option.Optimal_cost = 0
+			option.Unavailable = true
+			continue
+		}
+		option.Optimal_cost = per_craft
+
+		if slices.Contains(exclusions, option.Recipe.Recipe_id) {
+			continue
+		}
+
+		_, unit := craftUnitCosts(per_craft, option.Crafted_quantity, option.Expected_quantity, option.Reagent_savings)
+		if unit < best_craft {
+			best_craft = unit
+			recipe_id = option.Recipe.Recipe_id
+		}
+	}
+	if best_craft < best {
+		best = best_craft
+		method = globalTypes.ACQUIRE_CRAFT
+	}
+
+	decision := globalTypes.AcquisitionDecision{
+		Method:    method,
+		Recipe_id: recipe_id,
+	}
+	if !math.IsInf(best, 1) {
+		decision.Unit_cost = best
+		decision.Total_cost = best * node.Item_quantity
+	}
+	node.Acquisition = &decision
+
+	return best
+}
+
+/*
+Build a shopping list that only contains the items the make vs. buy analysis says should be bought.
+*/
+func buildOptimalShoppingList(intermediate_data globalTypes.OutputFormatObject) []globalTypes.ShoppingList {
+	return buildOptimalPlan(intermediate_data, shoppingPlan{}).shoppingList()
+}
+
+/*
+Add making the item following the make vs. buy analysis all the way down to a plan.
+The target item itself is always crafted when it has a recipe, since that is the point of the run.
+*/
+func buildOptimalPlan(intermediate_data globalTypes.OutputFormatObject, plan shoppingPlan) shoppingPlan {
+	if intermediate_data.Optimal != nil && intermediate_data.Optimal.Recipe_id != 0 {
+		if recipe, found := findOutputRecipe(intermediate_data, intermediate_data.Optimal.Recipe_id); found {
+			plan.craft(intermediate_data, recipe, intermediate_data.Required)
+			return plan
+		}
+	}
+	plan.acquire(intermediate_data, intermediate_data.Required)
+	return plan
+}
@@ -0,0 +1,83 @@
+package wow_crafting_profits
+
+import (
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func makeVsBuyTestTree(ah_low float64, part_vendor float64) globalTypes.ProfitAnalysisObject {
+	option := globalTypes.RecipeOption{
+		Crafted_quantity: 1,
+		Prices: []globalTypes.ProfitAnalysisObject{
+			{Item_id: 2, Item_quantity: 3, Vendor_price: part_vendor},
+		},
+	}
+	option.Recipe.Recipe_id = 77
+	return globalTypes.ProfitAnalysisObject{
+		Item_id:        1,
+		Item_quantity:  2,
+		Ah_price:       globalTypes.AHItemPriceObject{Total_sales: 5, Low: ah_low, Price: ah_low},
+		Recipe_options: []globalTypes.RecipeOption{option},
+	}
+}
+
+func TestDecideAcquisition(t *testing.T) {
+	tests := []struct {
+		name       string
+		tree       globalTypes.ProfitAnalysisObject
+		exclusions []uint
+		wantMethod globalTypes.AcquisitionMethod
+		wantUnit   float64
+		wantTotal  float64
+	}{
+		{
+			name:       "crafting is cheaper",
+			tree:       makeVsBuyTestTree(100, 10),
+			wantMethod: globalTypes.ACQUIRE_CRAFT,
+			wantUnit:   30,
+			wantTotal:  60,
+		},
+		{
+			name:       "buying is cheaper",
+			tree:       makeVsBuyTestTree(20, 10),
+			wantMethod: globalTypes.ACQUIRE_BUY_AH,
+			wantUnit:   20,
+			wantTotal:  40,
+		},
+		{
+			name:       "excluded recipe is never crafted",
+			tree:       makeVsBuyTestTree(100, 10),
+			exclusions: []uint{77},
+			wantMethod: globalTypes.ACQUIRE_BUY_AH,
+			wantUnit:   100,
+			wantTotal:  200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decideAcquisition(&tt.tree, tt.exclusions)
+			got := tt.tree.Acquisition
+			if got.Method != tt.wantMethod || got.Unit_cost != tt.wantUnit || got.Total_cost != tt.wantTotal {
+				t.Errorf("decideAcquisition() = %+v, want %s %v %v", *got, tt.wantMethod, tt.wantUnit, tt.wantTotal)
+			}
+			if part := tt.tree.Recipe_options[0].Prices[0].Acquisition; part == nil || part.Method != globalTypes.ACQUIRE_BUY_VENDOR {
+				t.Errorf("decideAcquisition() did not mark the reagent as bought from a vendor: %+v", part)
+			}
+		})
+	}
+}
+
+func TestDecideAcquisitionUnavailable(t *testing.T) {
+	// The reagent is neither listed nor sold by vendors
+	tree := makeVsBuyTestTree(100, 0)
+	decideAcquisition(&tree, nil)
+
+	if got := tree.Acquisition; got.Method != globalTypes.ACQUIRE_BUY_AH || got.Unit_cost != 100 {
+		t.Errorf("decideAcquisition() = %+v, want the item bought for 100", *got)
+	}
+	if option := tree.Recipe_options[0]; !option.Unavailable || option.Optimal_cost != 0 {
+		t.Errorf("decideAcquisition() recipe = unavailable %v cost %v, want an unavailable recipe", option.Unavailable, option.Optimal_cost)
+	}
+}
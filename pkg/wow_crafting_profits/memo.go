@@ -0,0 +1,246 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+// What a sub-analysis depends on, analyses are memoized for a single unit and repriced for the quantity each caller needs
+type analysisKey struct {
+	item_id uint
+	depth   uint
+}
+
+type analysisMemoEntry struct {
+	done   chan struct{}
+	result globalTypes.ProfitAnalysisObject
+	err    error
+}
+
+// Context key of the memoized analysis a call is made for, when there is one
+type memoParentKey struct{}
+
+/*
+Profit analyses computed during a single run, so a reagent shared by several recipes is only analyzed once.
+Concurrent requests for the same analysis wait for the first one to finish. Every analysis in flight records
+the analyses it is waiting on, and a request that would wait on itself through them is refused with an error.
+*/
+type analysisMemo struct {
+	mutex    sync.Mutex
+	entries  map[analysisKey]*analysisMemoEntry
+	waits    map[analysisKey]map[analysisKey]uint // Analyses in flight and how many of their calls wait on each other analysis
+	computed uint
+	reused   uint
+}
+
+func newAnalysisMemo() *analysisMemo {
+	return &analysisMemo{
+		entries: make(map[analysisKey]*analysisMemoEntry),
+		waits:   make(map[analysisKey]map[analysisKey]uint),
+	}
+}
+
+/*
+Return the memoized analysis for key, computing it if this is the first request.
+compute is given a context that marks the calls it makes as made for key.
+*/
+func (memo *analysisMemo) get(ctx context.Context, key analysisKey, compute func(context.Context) (globalTypes.ProfitAnalysisObject, error)) (globalTypes.ProfitAnalysisObject, error) {
+	parent, has_parent := ctx.Value(memoParentKey{}).(analysisKey)
+
+	memo.mutex.Lock()
+	entry, found := memo.entries[key]
+	if found {
+		memo.reused++
+		select {
+		case <-entry.done:
+			memo.mutex.Unlock()
+			return entry.result, entry.err
+		default:
+		}
+		if has_parent {
+			if memo.waitsOn(key, parent) {
+				memo.mutex.Unlock()
+				return globalTypes.ProfitAnalysisObject{}, fmt.Errorf("analysis of item %d depends on itself", key.item_id)
+			}
+			memo.addWait(parent, key)
+			defer memo.removeWait(parent, key)
+		}
+		memo.mutex.Unlock()
+		select {
+		case <-entry.done:
+			return entry.result, entry.err
+		case <-ctx.Done():
+			return globalTypes.ProfitAnalysisObject{}, ctx.Err()
+		}
+	}
+	entry = &analysisMemoEntry{done: make(chan struct{})}
+	memo.entries[key] = entry
+	memo.computed++
+	if has_parent {
+		memo.addWait(parent, key)
+	}
+	memo.mutex.Unlock()
+
+	entry.result, entry.err = compute(context.WithValue(ctx, memoParentKey{}, key))
+
+	memo.mutex.Lock()
+	delete(memo.waits, key)
+	memo.mutex.Unlock()
+	if has_parent {
+		memo.removeWait(parent, key)
+	}
+	close(entry.done)
+	return entry.result, entry.err
+}
+
+// Whether the analysis from is waiting on to, directly or through other analyses. The caller holds the mutex.
+func (memo *analysisMemo) waitsOn(from analysisKey, to analysisKey) bool {
+	seen := map[analysisKey]bool{from: true}
+	pending := []analysisKey{from}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == to {
+			return true
+		}
+		for next := range memo.waits[current] {
+			if !seen[next] {
+				seen[next] = true
+				pending = append(pending, next)
+			}
+		}
+	}
+	return false
+}
+
+// Record that a call of the analysis from waits on the analysis to. The caller holds the mutex.
+func (memo *analysisMemo) addWait(from analysisKey, to analysisKey) {
+	if memo.waits[from] == nil {
+		memo.waits[from] = make(map[analysisKey]uint)
+	}
+	memo.waits[from][to]++
+}
+
+func (memo *analysisMemo) removeWait(from analysisKey, to analysisKey) {
+	memo.mutex.Lock()
+	defer memo.mutex.Unlock()
+	if memo.waits[from][to] <= 1 {
+		delete(memo.waits[from], to)
+		return
+	}
+	memo.waits[from][to]--
+}
+
+func (memo *analysisMemo) stats() globalTypes.RunStats {
+	if memo == nil {
+		return globalTypes.RunStats{}
+	}
+	memo.mutex.Lock()
+	defer memo.mutex.Unlock()
+	return globalTypes.RunStats{
+		Analyses:  memo.computed,
+		Memo_hits: memo.reused,
+	}
+}
+
+/*
+Analyze an item, reusing an earlier analysis of the same item from this run when there is one.
+Analyses are made for a single unit and every auction price in them is walked again for the quantity required,
+so recipe, rank and tier choices are made at unit prices. Depth only changes the result when a max depth is set,
+otherwise it only matters whether the item is the one being analyzed.
+*/
+func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globalTypes.RegionCode, server globalTypes.RealmName, character_professions []globalTypes.CharacterProfession, item globalTypes.ItemSoftIdentity, qauntity uint, required float64, depth uint, passed_ah *BlizzardApi.Auctions, passedCyclicLinks *globalTypes.SkillTierCyclicLinks) (globalTypes.ProfitAnalysisObject, error) {
+	if cpc.memo == nil || item.ItemId == 0 {
+		return cpc.analyzeItem(ctx, region, server, character_professions, item, qauntity, required, depth, passed_ah, passedCyclicLinks)
+	}
+	key := analysisKey{
+		item_id: item.ItemId,
+		depth:   depth,
+	}
+	if cpc.maxDepth == 0 {
+		key.depth = min(depth, 1)
+	}
+	analysis, err := cpc.memo.get(ctx, key, func(ctx context.Context) (globalTypes.ProfitAnalysisObject, error) {
+		return cpc.analyzeItem(ctx, region, server, character_professions, item, qauntity, 1, depth, passed_ah, passedCyclicLinks)
+	})
+	if err != nil {
+		return globalTypes.ProfitAnalysisObject{}, err
+	}
+	realm_id, err := cpc.Helper.GetConnectedRealmId(ctx, server, region)
+	if err != nil {
+		return globalTypes.ProfitAnalysisObject{}, err
+	}
+	analysis = cpc.rescaleAnalysis(ctx, region, realm_id, analysis, required)
+	analysis.Item_quantity = float64(qauntity)
+	return analysis, nil
+}
+
+/*
+Copy an analysis with every auction price in it walked again for factor times the quantity it was priced for.
+Memoized analyses are shared, so nothing reachable from the original is changed.
+Cyclic conversions are not solved again, the chain chosen at single unit prices is kept and only the
+source's auction price is walked again for the larger quantity.
+*/
+func (cpc *WoWCpCRunner) rescaleAnalysis(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, analysis globalTypes.ProfitAnalysisObject, factor float64) globalTypes.ProfitAnalysisObject {
+	analysis.Ah_price = cpc.rescaleAHPrice(ctx, region, realm_id, analysis.Ah_price, factor)
+
+	analysis.Quality_prices = slices.Clone(analysis.Quality_prices)
+	for i := range analysis.Quality_prices {
+		analysis.Quality_prices[i].Ah = cpc.rescaleAHPrice(ctx, region, realm_id, analysis.Quality_prices[i].Ah, factor)
+	}
+	analysis.Bonus_prices = slices.Clone(analysis.Bonus_prices)
+	for i := range analysis.Bonus_prices {
+		analysis.Bonus_prices[i].Ah = cpc.rescaleAHPrice(ctx, region, realm_id, analysis.Bonus_prices[i].Ah, factor)
+	}
+
+	if analysis.Cyclic_conversion != nil {
+		conversion := *analysis.Cyclic_conversion
+		conversion.Source_ah = cpc.rescaleAHPrice(ctx, region, realm_id, conversion.Source_ah, factor)
+		if conversion.Source_vendor == 0 && conversion.Source_ah.Total_sales > 0 {
+			conversion.Unit_cost = conversion.Source_ah.Price * conversion.Ratio
+		}
+		analysis.Cyclic_conversion = &conversion
+	}
+
+	analysis.Recipe_options = slices.Clone(analysis.Recipe_options)
+	for i := range analysis.Recipe_options {
+		option := &analysis.Recipe_options[i]
+		option.Rank_ah = cpc.rescaleAHPrice(ctx, region, realm_id, option.Rank_ah, factor)
+
+		option.Slots = slices.Clone(option.Slots)
+		for j := range option.Slots {
+			option.Slots[j].Options = slices.Clone(option.Slots[j].Options)
+			for k := range option.Slots[j].Options {
+				option.Slots[j].Options[k].Ah = cpc.rescaleAHPrice(ctx, region, realm_id, option.Slots[j].Options[k].Ah, factor)
+			}
+		}
+
+		option.Prices = slices.Clone(option.Prices)
+		for j := range option.Prices {
+			option.Prices[j] = cpc.rescaleAnalysis(ctx, region, realm_id, option.Prices[j], factor)
+		}
+	}
+	return analysis
+}
+
+// Walk the order book again for factor times the quantity an auction price was found for
+func (cpc *WoWCpCRunner) rescaleAHPrice(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, price globalTypes.AHItemPriceObject, factor float64) globalTypes.AHItemPriceObject {
+	if price.Total_sales == 0 || factor == 1 {
+		return price
+	}
+	auctions := filterAuctions(cpc.itemAuctions(price.Item_id), func(auction BlizzardApi.Auction) bool {
+		return auctionMatchesBonus(auction, price.Bonus)
+	})
+	return cpc.priceAuctions(ctx, PriceRequest{
+		Item_id:  price.Item_id,
+		Bonus:    price.Bonus,
+		Region:   region,
+		Realm_id: realm_id,
+		Quantity: price.Required * factor,
+	}, auctions)
+}
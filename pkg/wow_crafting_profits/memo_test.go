@@ -0,0 +1,133 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestAnalysisMemo(t *testing.T) {
+	memo := newAnalysisMemo()
+	var (
+		mutex sync.Mutex
+		calls = make(map[analysisKey]int)
+		wg    sync.WaitGroup
+	)
+	keys := []analysisKey{
+		{item_id: 1, depth: 1},
+		{item_id: 1, depth: 2},
+		{item_id: 2, depth: 1},
+	}
+
+	for i := 0; i < 10; i++ {
+		for _, key := range keys {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, err := memo.get(context.Background(), key, func(context.Context) (globalTypes.ProfitAnalysisObject, error) {
+					mutex.Lock()
+					calls[key]++
+					mutex.Unlock()
+					return globalTypes.ProfitAnalysisObject{Item_id: key.item_id, Item_quantity: float64(key.depth)}, nil
+				})
+				if err != nil || got.Item_id != key.item_id || got.Item_quantity != float64(key.depth) {
+					t.Errorf("get(%+v) = %+v, %v", key, got, err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for _, key := range keys {
+		if calls[key] != 1 {
+			t.Errorf("analysis %+v computed %d times, want 1", key, calls[key])
+		}
+	}
+	if stats := memo.stats(); stats.Analyses != 3 || stats.Memo_hits != 27 {
+		t.Errorf("stats() = %+v, want 3 analyses and 27 memo hits", stats)
+	}
+}
+
+func TestAnalysisMemoCycles(t *testing.T) {
+	t.Run("item needs itself", func(t *testing.T) {
+		memo := newAnalysisMemo()
+		key := analysisKey{item_id: 1, depth: 1}
+		_, err := memo.get(context.Background(), key, func(ctx context.Context) (globalTypes.ProfitAnalysisObject, error) {
+			return memo.get(ctx, key, func(context.Context) (globalTypes.ProfitAnalysisObject, error) {
+				t.Error("the analysis in flight was computed again")
+				return globalTypes.ProfitAnalysisObject{}, nil
+			})
+		})
+		if err == nil {
+			t.Error("get() returned no error for an analysis that needs itself")
+		}
+	})
+
+	t.Run("items need each other", func(t *testing.T) {
+		memo := newAnalysisMemo()
+		first := analysisKey{item_id: 1, depth: 1}
+		second := analysisKey{item_id: 2, depth: 1}
+		both_started := make(chan struct{})
+		var started sync.WaitGroup
+		started.Add(2)
+		go func() {
+			started.Wait()
+			close(both_started)
+		}()
+		needs := func(other analysisKey) func(context.Context) (globalTypes.ProfitAnalysisObject, error) {
+			return func(ctx context.Context) (globalTypes.ProfitAnalysisObject, error) {
+				started.Done()
+				<-both_started
+				return memo.get(ctx, other, func(context.Context) (globalTypes.ProfitAnalysisObject, error) {
+					t.Errorf("analysis %+v in flight was computed again", other)
+					return globalTypes.ProfitAnalysisObject{}, nil
+				})
+			}
+		}
+
+		errs := make(chan error, 2)
+		go func() {
+			_, err := memo.get(context.Background(), first, needs(second))
+			errs <- err
+		}()
+		go func() {
+			_, err := memo.get(context.Background(), second, needs(first))
+			errs <- err
+		}()
+		for range 2 {
+			if err := <-errs; err == nil {
+				t.Error("get() returned no error for analyses that need each other")
+			}
+		}
+	})
+}
+
+func TestOfflineRunMemoStats(t *testing.T) {
+	// Test Tonic needs Test Herb directly and through the Test Potion it is made from
+	results, err := offlineTestRunner(t).RunWithJSONConfig(context.Background(), offlineTestConfig(1002, 2))
+	if err != nil {
+		t.Fatalf("RunWithJSONConfig() error = %v", err)
+	}
+	if results.Stats.Analyses == 0 || results.Stats.Memo_hits == 0 {
+		t.Errorf("RunWithJSONConfig() stats = %+v, want analyses counted and the shared reagent reused", results.Stats)
+	}
+
+	tonic := results.Price
+	if len(tonic.Recipe_options) != 1 || len(tonic.Recipe_options[0].Prices) != 2 {
+		t.Fatalf("RunWithJSONConfig() analyzed %+v, want one recipe with two parts", tonic.Recipe_options)
+	}
+	direct_herb := tonic.Recipe_options[0].Prices[0]
+	potion := tonic.Recipe_options[0].Prices[1]
+	if len(potion.Recipe_options) == 0 {
+		t.Fatalf("Test Potion was not analyzed as a crafted reagent")
+	}
+	potion_herb := potion.Recipe_options[0].Prices[0]
+	if direct_herb.Ah_price.Required != 4 || potion_herb.Ah_price.Required != 6 {
+		t.Errorf("Test Herb priced for %v directly and %v through Test Potion, want 4 and 6", direct_herb.Ah_price.Required, potion_herb.Ah_price.Required)
+	}
+	if direct_herb.Ah_price.Fill_cost != 600 || potion_herb.Ah_price.Fill_cost != 1000 {
+		t.Errorf("Test Herb costs %v directly and %v through Test Potion, want the order book walked to 600 and 1000", direct_herb.Ah_price.Fill_cost, potion_herb.Ah_price.Fill_cost)
+	}
+}
@@ -0,0 +1,82 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/blizzard_api_call"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/blizzard_api_helpers"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func offlineTestRunner(t *testing.T) *WoWCpCRunner {
+	t.Helper()
+	logger := cpclog.NewCpCLog(cpclog.GetLevel("error"))
+	helper, err := blizzard_api_helpers.NewOfflineBlizzardApiHelper("testdata/offline_data.json", logger)
+	if err != nil {
+		t.Fatalf("NewOfflineBlizzardApiHelper() error = %v", err)
+	}
+	auctions, err := LoadAuctionSnapshot("testdata/auctions.json")
+	if err != nil {
+		t.Fatalf("LoadAuctionSnapshot() error = %v", err)
+	}
+	cpc := &WoWCpCRunner{
+		Helper:   helper,
+		Logger:   logger,
+		Auctions: auctions,
+	}
+	cpc.staticSources.RootDirectory = "../../static_files"
+	return cpc
+}
+
+// The test vial is sold by vendors but is on neither vendor list, so the test runs price it themselves
+var offline_vendor_prices = map[globalTypes.ItemID]float64{2002: 50}
+
+func offlineTestConfig(item globalTypes.ItemID, count uint) *globalTypes.RunConfiguration {
+	addon := globalTypes.AddonData{Professions: []globalTypes.CharacterProfession{"Alchemy"}}
+	addon.Realm.Realm_name = "Hyjal"
+	addon.Realm.Region_name = "us"
+	config := globalTypes.NewRunConfig(&addon, globalTypes.ItemSoftIdentity{ItemId: item}, count)
+	config.Vendor_prices = offline_vendor_prices
+	return config
+}
+
+func TestOfflineRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      uint
+		wantCost   float64
+		wantProfit float64
+	}{
+		// 3 herbs from the two cheapest listings and a vendor vial
+		{name: "single craft", count: 1, wantCost: 450, wantProfit: 9020},
+		// 30 herbs walk up the order book
+		{name: "many crafts", count: 10, wantCost: 630, wantProfit: 8840},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := offlineTestRunner(t).RunWithJSONConfig(context.Background(), offlineTestConfig(1001, tt.count))
+			if err != nil {
+				t.Fatalf("RunWithJSONConfig() error = %v", err)
+			}
+			if len(results.Profits) != 1 {
+				t.Fatalf("RunWithJSONConfig() profits = %+v, want one recipe", results.Profits)
+			}
+			profit := results.Profits[0]
+			if math.Abs(profit.Crafting_cost-tt.wantCost) > 0.0001 || math.Abs(profit.Net_profit-tt.wantProfit) > 0.0001 {
+				t.Errorf("RunWithJSONConfig() cost %v profit %v, want %v %v", profit.Crafting_cost, profit.Net_profit, tt.wantCost, tt.wantProfit)
+			}
+		})
+	}
+}
+
+func TestOfflineRunMissingData(t *testing.T) {
+	_, err := offlineTestRunner(t).RunWithJSONConfig(context.Background(), offlineTestConfig(9999, 1))
+	if !errors.Is(err, blizzard_api_call.ErrOffline) {
+		t.Errorf("RunWithJSONConfig() error = %v, want %v", err, blizzard_api_call.ErrOffline)
+	}
+}
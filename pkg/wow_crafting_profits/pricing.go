@@ -0,0 +1,362 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/util"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/auction_history"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+const (
+	default_percentile   float64 = 25
+	default_history_days uint    = 14
+)
+
+// Quantity units of an item listed on the auction house at Price each
+type PriceListing struct {
+	Price    float64
+	Quantity uint
+}
+
+// Everything a pricing strategy may need to value an item
+type PriceRequest struct {
+	Item_id  globalTypes.ItemID
+	Bonus    uint
+	Region   globalTypes.RegionCode
+	Realm_id globalTypes.ConnectedRealmID
+	Quantity float64        // How many units are needed
+	Listings []PriceListing // Current auctions, cheapest first
+}
+
+/*
+A PricingStrategy decides what a single unit of an item costs on the auction house.
+Strategies return false when they cannot price the item.
+*/
+type PricingStrategy interface {
+	Name() string
+	UnitPrice(ctx context.Context, request PriceRequest) (float64, bool)
+}
+
+// Price items at the cheapest listing
+type MinBuyoutPricing struct{}
+
+func (MinBuyoutPricing) Name() string { return "min" }
+
+func (MinBuyoutPricing) UnitPrice(ctx context.Context, request PriceRequest) (float64, bool) {
+	if len(request.Listings) == 0 {
+		return 0, false
+	}
+	return request.Listings[0].Price, true
+}
+
+// Price items at the average of every listing, weighted by the quantity listed
+type WeightedAveragePricing struct{}
+
+func (WeightedAveragePricing) Name() string { return "average" }
+
+func (WeightedAveragePricing) UnitPrice(ctx context.Context, request PriceRequest) (float64, bool) {
+	var total, count float64
+	for _, listing := range request.Listings {
+		total += listing.Price * float64(listing.Quantity)
+		count += float64(listing.Quantity)
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / count, true
+}
+
+// Price items at the median of every unit listed
+type MedianPricing struct{}
+
+func (MedianPricing) Name() string { return "median" }
+
+func (MedianPricing) UnitPrice(ctx context.Context, request PriceRequest) (float64, bool) {
+	prices := make(map[float64]uint64, len(request.Listings))
+	for _, listing := range request.Listings {
+		prices[listing.Price] += uint64(listing.Quantity)
+	}
+	median, err := util.MedianFromMap(prices)
+	if err != nil {
+		return 0, false
+	}
+	return median, true
+}
+
+// Price items at the Nth percentile of every unit listed, 0 is the cheapest unit and 100 the most expensive
+type PercentilePricing struct {
+	Percentile float64
+}
+
+func (p PercentilePricing) Name() string { return fmt.Sprintf("percentile:%g", p.Percentile) }
+
+func (p PercentilePricing) UnitPrice(ctx context.Context, request PriceRequest) (float64, bool) {
+	var total float64
+	for _, listing := range request.Listings {
+		total += float64(listing.Quantity)
+	}
+	if total == 0 {
+		return 0, false
+	}
+	target := total * min(max(p.Percentile, 0), 100) / 100
+	var seen float64
+	for _, listing := range request.Listings {
+		seen += float64(listing.Quantity)
+		if seen >= target {
+			return listing.Price, true
+		}
+	}
+	return request.Listings[len(request.Listings)-1].Price, true
+}
+
+/*
+Price items at the average cost of buying Units of them, cheapest listings first.
+When Units is zero the quantity being priced is used. Any shortfall in supply is
+priced at the most expensive listing.
+*/
+type OrderBookPricing struct {
+	Units float64
+}
+
+func (p OrderBookPricing) Name() string {
+	if p.Units == 0 {
+		return "orderbook"
+	}
+	return fmt.Sprintf("orderbook:%g", p.Units)
+}
+
+func (p OrderBookPricing) UnitPrice(ctx context.Context, request PriceRequest) (float64, bool) {
+	units := p.Units
+	if units == 0 {
+		units = request.Quantity
+	}
+	if units <= 0 {
+		units = 1
+	}
+	cost, filled := walkOrderBook(request.Listings, units)
+	if filled == 0 {
+		return 0, false
+	}
+	if filled < units {
+		cost += (units - filled) * request.Listings[len(request.Listings)-1].Price
+	}
+	return cost / units, true
+}
+
+// Buy up to units from the listings, cheapest first, returning the total cost and how many units were bought
+func walkOrderBook(listings []PriceListing, units float64) (cost float64, filled float64) {
+	for _, listing := range listings {
+		if filled >= units {
+			break
+		}
+		take := min(float64(listing.Quantity), units-filled)
+		cost += take * listing.Price
+		filled += take
+	}
+	return cost, filled
+}
+
+// Price items at their average price on the realm over the last Days days of auction history
+type HistoricalAveragePricing struct {
+	History *auction_history.AuctionHistoryServer
+	Days    uint
+
+	averages *historyAverages // Averages already looked up by this run, nil looks every price up again
+}
+
+// What a historical average is looked up for
+type historyAverageKey struct {
+	item_id  globalTypes.ItemID
+	bonus    uint
+	region   globalTypes.RegionCode
+	realm_id globalTypes.ConnectedRealmID
+}
+
+type historyAverage struct {
+	price float64
+	found bool
+}
+
+// Historical averages looked up during a single run, so each item is only queried once however often it is priced
+type historyAverages struct {
+	mutex    sync.Mutex
+	averages map[historyAverageKey]historyAverage
+}
+
+// Return the cached average for key, looking it up the first time
+func (cache *historyAverages) get(key historyAverageKey, lookup func() (float64, bool)) (float64, bool) {
+	cache.mutex.Lock()
+	average, found := cache.averages[key]
+	cache.mutex.Unlock()
+	if found {
+		return average.price, average.found
+	}
+
+	average.price, average.found = lookup()
+	cache.mutex.Lock()
+	cache.averages[key] = average
+	cache.mutex.Unlock()
+	return average.price, average.found
+}
+
+// A copy of the strategy with its own cache of averages, for a single run
+func (p HistoricalAveragePricing) forRun() HistoricalAveragePricing {
+	p.averages = &historyAverages{averages: make(map[historyAverageKey]historyAverage)}
+	return p
+}
+
+func (p HistoricalAveragePricing) Name() string { return fmt.Sprintf("history:%d", p.days()) }
+
+func (p HistoricalAveragePricing) days() uint {
+	if p.Days == 0 {
+		return default_history_days
+	}
+	return p.Days
+}
+
+func (p HistoricalAveragePricing) UnitPrice(ctx context.Context, request PriceRequest) (float64, bool) {
+	if p.History == nil {
+		return 0, false
+	}
+	if p.averages == nil {
+		return p.lookupAverage(ctx, request)
+	}
+	return p.averages.get(historyAverageKey{
+		item_id:  request.Item_id,
+		bonus:    request.Bonus,
+		region:   request.Region,
+		realm_id: request.Realm_id,
+	}, func() (float64, bool) {
+		return p.lookupAverage(ctx, request)
+	})
+}
+
+func (p HistoricalAveragePricing) lookupAverage(ctx context.Context, request PriceRequest) (float64, bool) {
+	var bonuses []uint
+	if request.Bonus != 0 {
+		bonuses = []uint{request.Bonus}
+	}
+	end := time.Now()
+	start := end.AddDate(0, 0, -int(p.days()))
+	summary, err := p.History.GetAuctions(ctx, globalTypes.ItemSoftIdentity{ItemId: request.Item_id}, globalTypes.ConnectedRealmSoftIentity{Id: request.Realm_id}, request.Region, bonuses, start, end)
+	if err != nil || summary.Avg <= 0 {
+		return 0, false
+	}
+	return summary.Avg, true
+}
+
+/*
+Parse a pricing strategy from its name, optionally followed by a colon and a parameter:
+min, average, median, percentile[:N], orderbook[:units] or history[:days].
+An empty name selects orderbook, which prices each reagent at the cost of filling the quantity the run needs.
+*/
+func ParsePricingStrategy(spec string, history *auction_history.AuctionHistoryServer) (PricingStrategy, error) {
+	name, param, has_param := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	var value float64
+	if has_param {
+		parsed, err := strconv.ParseFloat(param, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid parameter %q for pricing strategy %s", param, name)
+		}
+		value = parsed
+	}
+
+	switch name {
+	case "min":
+		return MinBuyoutPricing{}, nil
+	case "average":
+		return WeightedAveragePricing{}, nil
+	case "median":
+		return MedianPricing{}, nil
+	case "percentile":
+		if !has_param {
+			value = default_percentile
+		}
+		if value > 100 {
+			return nil, fmt.Errorf("percentile must be between 0 and 100, got %g", value)
+		}
+		return PercentilePricing{Percentile: value}, nil
+	case "", "orderbook":
+		return OrderBookPricing{Units: value}, nil
+	case "history":
+		if history == nil {
+			return nil, fmt.Errorf("pricing strategy history requires auction history to be available")
+		}
+		return HistoricalAveragePricing{History: history, Days: uint(value)}, nil
+	default:
+		return nil, fmt.Errorf("%s is not a known pricing strategy. Valid strategies include 'min', 'average', 'median', 'percentile', 'orderbook' and 'history'", spec)
+	}
+}
+
+// The strategy a run should use, the run configuration wins over the runner's default
+func (cpc *WoWCpCRunner) pricingFor(json_config *globalTypes.RunConfiguration) (PricingStrategy, error) {
+	if json_config.Pricing_strategy != "" {
+		return ParsePricingStrategy(json_config.Pricing_strategy, cpc.History)
+	}
+	if cpc.Pricing != nil {
+		return cpc.Pricing, nil
+	}
+	return OrderBookPricing{}, nil
+}
+
+/*
+Find the value of an item on the auction house, filling in the unit price chosen by the runner's pricing strategy
+and the cost of buying the quantity required from the cheapest listings up.
+*/
+func (cpc *WoWCpCRunner) getAHPrice(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, item_id globalTypes.ItemID, bonus_level_required uint, quantity float64) globalTypes.AHItemPriceObject {
+	listed := cpc.itemAuctions(item_id)
+	auctions := filterAuctions(listed, func(auction BlizzardApi.Auction) bool {
+		return auctionMatchesBonus(auction, bonus_level_required)
+	})
+	price := cpc.priceAuctions(ctx, PriceRequest{
+		Item_id:  item_id,
+		Bonus:    bonus_level_required,
+		Region:   region,
+		Realm_id: realm_id,
+		Quantity: quantity,
+	}, auctions)
+	if cpc.trace != nil {
+		cpc.traceAuctionMatch(item_id, bonus_level_required, quantity, listed, auctions, price)
+	}
+	return price
+}
+
+/*
+Price a set of auctions for a single item. The strategy price falls back to the cheapest listing
+if the strategy cannot price the item.
+*/
+func (cpc *WoWCpCRunner) priceAuctions(ctx context.Context, request PriceRequest, auctions []BlizzardApi.Auction) globalTypes.AHItemPriceObject {
+	price := getAHItemPrice(auctions)
+	price.Item_id = request.Item_id
+	price.Bonus = request.Bonus
+	if price.Total_sales == 0 {
+		return price
+	}
+
+	request.Listings = auctionPriceListings(auctions)
+	fill_cost, filled := walkOrderBook(request.Listings, request.Quantity)
+	price.Required = request.Quantity
+	price.Fill_cost = fill_cost
+	if filled < request.Quantity {
+		price.Insufficient_supply = true
+		cpc.Logger.Debugf("Only %g of %g needed units of %d are listed on the auction house", filled, request.Quantity, request.Item_id)
+	}
+
+	strategy := cpc.Pricing
+	if strategy == nil {
+		strategy = OrderBookPricing{}
+	}
+	if unit_price, found := strategy.UnitPrice(ctx, request); found {
+		price.Price = unit_price
+	} else {
+		price.Price = price.Low
+	}
+	return price
+}
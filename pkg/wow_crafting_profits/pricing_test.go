@@ -0,0 +1,209 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+func TestPricingStrategies(t *testing.T) {
+	request := PriceRequest{
+		Quantity: 15,
+		Listings: []PriceListing{
+			{Price: 10, Quantity: 10},
+			{Price: 20, Quantity: 5},
+			{Price: 100, Quantity: 5},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		strategy PricingStrategy
+		want     float64
+	}{
+		{name: "min buyout", strategy: MinBuyoutPricing{}, want: 10},
+		{name: "weighted average", strategy: WeightedAveragePricing{}, want: 35},
+		{name: "median", strategy: MedianPricing{}, want: 15},
+		{name: "percentile", strategy: PercentilePricing{Percentile: 75}, want: 20},
+		{name: "order book for the requested quantity", strategy: OrderBookPricing{}, want: 200.0 / 15},
+		{name: "order book for fixed units", strategy: OrderBookPricing{Units: 5}, want: 10},
+		{name: "order book beyond supply", strategy: OrderBookPricing{Units: 25}, want: 1200.0 / 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := tt.strategy.UnitPrice(context.Background(), request)
+			if !found {
+				t.Fatalf("%s.UnitPrice() found no price", tt.strategy.Name())
+			}
+			if got != tt.want {
+				t.Errorf("%s.UnitPrice() = %v, want %v", tt.strategy.Name(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePricingStrategy(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantName string
+		wantErr  bool
+	}{
+		{spec: "", wantName: "orderbook"},
+		{spec: "min", wantName: "min"},
+		{spec: "Average", wantName: "average"},
+		{spec: "percentile", wantName: "percentile:25"},
+		{spec: "percentile:90", wantName: "percentile:90"},
+		{spec: "percentile:120", wantErr: true},
+		{spec: "orderbook:500", wantName: "orderbook:500"},
+		{spec: "history", wantErr: true},
+		{spec: "cheapest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParsePricingStrategy(tt.spec, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePricingStrategy(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if err == nil && got.Name() != tt.wantName {
+				t.Errorf("ParsePricingStrategy(%q) = %s, want %s", tt.spec, got.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestHistoryAverages(t *testing.T) {
+	averages := HistoricalAveragePricing{}.forRun().averages
+	lookups := make(map[historyAverageKey]int)
+	lookup := func(key historyAverageKey, price float64) func() (float64, bool) {
+		return func() (float64, bool) {
+			lookups[key]++
+			return price, price > 0
+		}
+	}
+
+	herb := historyAverageKey{item_id: 2001, region: "us", realm_id: 57}
+	herb_bonus := historyAverageKey{item_id: 2001, bonus: 1, region: "us", realm_id: 57}
+	unsold := historyAverageKey{item_id: 2002, region: "us", realm_id: 57}
+	for range 3 {
+		if price, found := averages.get(herb, lookup(herb, 150)); price != 150 || !found {
+			t.Errorf("get(herb) = %v %v, want 150", price, found)
+		}
+		if price, found := averages.get(herb_bonus, lookup(herb_bonus, 300)); price != 300 || !found {
+			t.Errorf("get(herb with bonus) = %v %v, want 300", price, found)
+		}
+		if _, found := averages.get(unsold, lookup(unsold, 0)); found {
+			t.Error("get(unsold) found an average")
+		}
+	}
+	for key, count := range lookups {
+		if count != 1 {
+			t.Errorf("average of %+v looked up %d times, want 1", key, count)
+		}
+	}
+}
+
+func TestGetAHPriceOrderBookDepth(t *testing.T) {
+	var cheap, dear BlizzardApi.Auction
+	cheap.Item.Id, cheap.Quantity, cheap.Unit_price = 1, 100, 10
+	dear.Item.Id, dear.Quantity, dear.Unit_price = 1, 400, 50
+
+	cpc := &WoWCpCRunner{
+		Logger:          cpclog.NewCpCLog(cpclog.GetLevel("error")),
+		indexedAuctions: map[globalTypes.ItemID][]BlizzardApi.Auction{1: {cheap, dear}},
+	}
+
+	tests := []struct {
+		name      string
+		required  float64
+		wantPrice float64
+		wantFill  float64
+		wantShort bool
+	}{
+		{name: "cheapest listing covers it", required: 50, wantPrice: 10, wantFill: 500},
+		{name: "walks up the ladder", required: 500, wantPrice: 42, wantFill: 21000},
+		{name: "not enough supply", required: 1000, wantPrice: 46, wantFill: 21000, wantShort: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cpc.getAHPrice(context.Background(), "us", 0, 1, 0, tt.required)
+			if got.Price != tt.wantPrice || got.Fill_cost != tt.wantFill || got.Insufficient_supply != tt.wantShort {
+				t.Errorf("getAHPrice() = price %v fill %v short %v, want %v %v %v", got.Price, got.Fill_cost, got.Insufficient_supply, tt.wantPrice, tt.wantFill, tt.wantShort)
+			}
+		})
+	}
+}
+
+func TestGetAHPriceCommodities(t *testing.T) {
+	var realm, commodity BlizzardApi.Auction
+	realm.Item.Id, realm.Quantity, realm.Buyout = 1, 1, 500
+	commodity.Item.Id, commodity.Quantity, commodity.Unit_price, commodity.Commodity = 2, 50, 20, true
+
+	cpc := &WoWCpCRunner{
+		Logger:            cpclog.NewCpCLog(cpclog.GetLevel("error")),
+		indexedAuctions:   map[globalTypes.ItemID][]BlizzardApi.Auction{1: {realm}},
+		commodityAuctions: map[globalTypes.ItemID][]BlizzardApi.Auction{2: {commodity}},
+	}
+	realm_runner := cpc.withAuctions(&BlizzardApi.Auctions{})
+
+	tests := []struct {
+		name          string
+		runner        *WoWCpCRunner
+		item_id       globalTypes.ItemID
+		wantSales     uint
+		wantCommodity bool
+	}{
+		{name: "realm listing", runner: cpc, item_id: 1, wantSales: 1},
+		{name: "commodity listing", runner: cpc, item_id: 2, wantSales: 50, wantCommodity: true},
+		{name: "commodities shared with other realms", runner: realm_runner, item_id: 2, wantSales: 50, wantCommodity: true},
+		{name: "realm listings are not shared", runner: realm_runner, item_id: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.runner.getAHPrice(context.Background(), "us", 0, tt.item_id, 0, 1)
+			if got.Total_sales != tt.wantSales || got.Commodity != tt.wantCommodity {
+				t.Errorf("getAHPrice() = %d sales commodity %v, want %d commodity %v", got.Total_sales, got.Commodity, tt.wantSales, tt.wantCommodity)
+			}
+		})
+	}
+}
+
+func TestRecipeCostCalculatorPrice(t *testing.T) {
+	ah_part := func(price globalTypes.AHItemPriceObject, quantity float64) globalTypes.ProfitAnalysisObject {
+		price.Total_sales = 10
+		return globalTypes.ProfitAnalysisObject{Ah_price: price, Item_quantity: quantity}
+	}
+	crafted := globalTypes.ProfitAnalysisObject{Item_quantity: 2}
+	crafted.Crafting_status.Craftable = true
+	crafted.Recipe_options = []globalTypes.RecipeOption{
+		{Prices: []globalTypes.ProfitAnalysisObject{ah_part(globalTypes.AHItemPriceObject{Price: 20, Median: 1}, 3)}},
+		{Prices: []globalTypes.ProfitAnalysisObject{ah_part(globalTypes.AHItemPriceObject{Price: 10, Median: 1}, 3)}},
+	}
+
+	tests := []struct {
+		name  string
+		parts []globalTypes.ProfitAnalysisObject
+		want  float64
+	}{
+		{name: "strategy price", parts: []globalTypes.ProfitAnalysisObject{ah_part(globalTypes.AHItemPriceObject{Price: 150, Median: 100}, 2)}, want: 300},
+		{name: "fill cost without a strategy price", parts: []globalTypes.ProfitAnalysisObject{ah_part(globalTypes.AHItemPriceObject{Required: 3, Fill_cost: 90}, 2)}, want: 60},
+		{name: "vendor", parts: []globalTypes.ProfitAnalysisObject{{Vendor_price: 50, Item_quantity: 1}}, want: 50},
+		{name: "cheapest recipe", parts: []globalTypes.ProfitAnalysisObject{crafted}, want: 60},
+	}
+
+	cpc := &WoWCpCRunner{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cpc.recipeCostCalculator(globalTypes.RecipeOption{Prices: tt.parts})
+			if got.Price != tt.want {
+				t.Errorf("recipeCostCalculator().Price = %v, want %v", got.Price, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package wow_crafting_profits
+
+import (
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+const (
+	ah_cut_rate              float64 = 0.05 // The auction house keeps 5% of every sale
+	deposit_rate_per_period  float64 = 0.15 // Deposits are 15% of the vendor sell price per 12 hours listed
+	deposit_period_hours     uint    = 12
+	default_listing_duration uint    = 24
+)
+
+/*
+Estimate the deposit for listing a single unit, deposits scale with both the vendor sell price and how long the auction runs.
+*/
+func estimateDeposit(sell_price float64, listing_duration uint) float64 {
+	if listing_duration == 0 {
+		listing_duration = default_listing_duration
+	}
+	return sell_price * deposit_rate_per_period * float64(listing_duration) / float64(deposit_period_hours)
+}
+
+/*
+Build a profit report for every recipe rank of the crafted item.
+Sale prices come from the rank specific auctions when available and the item's auctions otherwise,
+crafting costs come from the make vs. buy analysis and include the crafter's expected
+multicraft and resourcefulness, the naive cost is reported alongside. The deposit is treated as a cost, which is
+the worst case of a listing that expires without selling. Ranks with a part that can neither be bought nor made are left out.
+*/
+func calculateProfits(price_data globalTypes.ProfitAnalysisObject, intermediate_data globalTypes.OutputFormatObject, listing_duration uint) []globalTypes.ProfitReport {
+	profits := make([]globalTypes.ProfitReport, 0, len(intermediate_data.Recipes))
+
+	for _, recipe := range intermediate_data.Recipes {
+		if recipe.Unavailable {
+			continue
+		}
+		var sale_price float64
+		if recipe.Ah.Sales > 0 {
+			sale_price = recipe.Ah.Low
+		} else if intermediate_data.Ah.Sales > 0 {
+			sale_price = intermediate_data.Ah.Low
+		} else {
+			continue
+		}
+
+		craft_cost := recipe.Optimal_cost
+		if craft_cost == 0 {
+			craft_cost = recipe.Price
+		}
+		naive_cost, unit_cost := craftUnitCosts(craft_cost, recipe.Output.Value, recipe.Output.Expected, recipe.Reagent_savings)
+
+		report := globalTypes.ProfitReport{
+			Recipe_id:     recipe.Id,
+			Rank:          recipe.Rank,
+			Sale_price:    sale_price,
+			Crafting_cost: unit_cost,
+			Naive_cost:    naive_cost,
+			Ah_cut:        sale_price * ah_cut_rate,
+			Deposit:       estimateDeposit(price_data.Sell_price, listing_duration),
+			Quantity:      intermediate_data.Required,
+		}
+		report.Net_profit = report.Sale_price - report.Ah_cut - report.Deposit - report.Crafting_cost
+		if report.Crafting_cost > 0 {
+			report.Roi = report.Net_profit / report.Crafting_cost * 100
+		}
+		report.Total_net_profit = report.Net_profit * report.Quantity
+
+		profits = append(profits, report)
+	}
+
+	return profits
+}
@@ -0,0 +1,38 @@
+package wow_crafting_profits
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestCalculateProfits(t *testing.T) {
+	price_data := globalTypes.ProfitAnalysisObject{Sell_price: 100}
+	intermediate_data := globalTypes.OutputFormatObject{
+		Required: 3,
+		Ah:       globalTypes.OutputFormatPrice{Sales: 10, Low: 10000},
+		Recipes: []globalTypes.OutputFormatRecipe{
+			{Id: 1, Rank: 0, Output: globalTypes.OutpoutFormatRecipeOutput{Value: 2}, Optimal_cost: 8000},
+			{Id: 2, Rank: 1, Ah: globalTypes.OutputFormatPrice{Sales: 1, Low: 20000}, Optimal_cost: 5000},
+			{Id: 3, Rank: 2, Median: 1, Price: 6000},
+			{Id: 4, Rank: 3, Price: 100, Unavailable: true},
+		},
+	}
+
+	profits := calculateProfits(price_data, intermediate_data, 48)
+	if len(profits) != 3 {
+		t.Fatalf("calculateProfits() returned %d reports, want 3", len(profits))
+	}
+
+	want := []globalTypes.ProfitReport{
+		{Recipe_id: 1, Rank: 0, Sale_price: 10000, Crafting_cost: 4000, Ah_cut: 500, Deposit: 60, Net_profit: 5440, Roi: 136, Quantity: 3, Total_net_profit: 16320},
+		{Recipe_id: 2, Rank: 1, Sale_price: 20000, Crafting_cost: 5000, Ah_cut: 1000, Deposit: 60, Net_profit: 13940, Roi: 278.8, Quantity: 3, Total_net_profit: 41820},
+		{Recipe_id: 3, Rank: 2, Sale_price: 10000, Crafting_cost: 6000, Ah_cut: 500, Deposit: 60, Net_profit: 3440, Roi: 57.3333, Quantity: 3, Total_net_profit: 10320},
+	}
+	for i, got := range profits {
+		if got.Recipe_id != want[i].Recipe_id || got.Rank != want[i].Rank || math.Abs(got.Net_profit-want[i].Net_profit) > 0.001 || math.Abs(got.Roi-want[i].Roi) > 0.001 || math.Abs(got.Total_net_profit-want[i].Total_net_profit) > 0.001 || math.Abs(got.Deposit-want[i].Deposit) > 0.001 {
+			t.Errorf("calculateProfits()[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
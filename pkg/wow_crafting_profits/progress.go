@@ -0,0 +1,50 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/blizzard_api_call"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+/*
+Progress of a single run, passed to the runner's Progress callback each time an item is analyzed.
+Reports are made one at a time, so the callback does not need to be safe for concurrent use.
+*/
+type progressTracker struct {
+	report    func(globalTypes.RunProgress)
+	api_calls atomic.Uint64
+	mutex     sync.Mutex
+	progress  globalTypes.RunProgress
+}
+
+func newProgressTracker(report func(globalTypes.RunProgress)) *progressTracker {
+	if report == nil {
+		return nil
+	}
+	return &progressTracker{report: report}
+}
+
+// Count the Blizzard API calls made with the returned context towards this run
+func (tracker *progressTracker) track(ctx context.Context) context.Context {
+	if tracker == nil {
+		return ctx
+	}
+	return blizzard_api_call.WithCallCounter(ctx, &tracker.api_calls)
+}
+
+// Record that an item has been analyzed and report the run's progress
+func (tracker *progressTracker) itemAnalyzed(item_name string, depth uint) {
+	if tracker == nil {
+		return
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.progress.Items_analyzed++
+	tracker.progress.Api_calls = tracker.api_calls.Load()
+	tracker.progress.Depth = depth
+	tracker.progress.Item_name = item_name
+	tracker.report(tracker.progress)
+}
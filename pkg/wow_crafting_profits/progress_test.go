@@ -0,0 +1,45 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestOfflineRunProgress(t *testing.T) {
+	var reports []globalTypes.RunProgress
+	cpc := offlineTestRunner(t)
+	cpc.Progress = func(progress globalTypes.RunProgress) {
+		reports = append(reports, progress)
+	}
+
+	results, err := cpc.RunWithJSONConfig(context.Background(), offlineTestConfig(1001, 1))
+	if err != nil {
+		t.Fatalf("RunWithJSONConfig() error = %v", err)
+	}
+	if len(reports) == 0 {
+		t.Fatal("RunWithJSONConfig() reported no progress")
+	}
+	for i, report := range reports {
+		if report.Items_analyzed != uint(i+1) {
+			t.Errorf("report %d counted %d items, want %d", i, report.Items_analyzed, i+1)
+		}
+	}
+	if first := reports[0]; first.Depth != 0 || first.Item_name == "" {
+		t.Errorf("first report = %+v, want the analyzed item at depth 0", first)
+	}
+	if last := reports[len(reports)-1]; last.Items_analyzed != results.Stats.Analyses {
+		t.Errorf("reported %d items analyzed, stats counted %d", last.Items_analyzed, results.Stats.Analyses)
+	}
+}
+
+func TestOfflineRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := offlineTestRunner(t).RunWithJSONConfig(ctx, offlineTestConfig(1001, 1))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RunWithJSONConfig() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,55 @@
+package wow_crafting_profits
+
+import (
+	"fmt"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/text_output_helpers"
+)
+
+/*
+Decide whether a craftable reagent at depth should be bought rather than crafted, returning why or an empty string.
+The item being analyzed, at depth 0, is never pruned.
+*/
+func (cpc *WoWCpCRunner) pruneReason(item_id globalTypes.ItemID, depth uint, ah_price globalTypes.AHItemPriceObject) string {
+	if depth == 0 {
+		return ""
+	}
+	if cpc.rawMaterials != nil && cpc.rawMaterials.Has(item_id) {
+		return "raw material"
+	}
+	if cpc.maxDepth > 0 && depth >= cpc.maxDepth {
+		return fmt.Sprintf("deeper than max depth %d", cpc.maxDepth)
+	}
+	if cpc.minCraftValue > 0 && ah_price.Total_sales > 0 && ah_price.Price < cpc.minCraftValue {
+		return fmt.Sprintf("worth %s, below %s", text_output_helpers.GoldFormatter(ah_price.Price), text_output_helpers.GoldFormatter(cpc.minCraftValue))
+	}
+	return ""
+}
+
+// Every reagent pruned from an analysis, in the order they appear in the tree, listing each item once
+func prunedNodes(price_data globalTypes.ProfitAnalysisObject) []globalTypes.PrunedNode {
+	var (
+		nodes []globalTypes.PrunedNode
+		seen  = make(map[uint]bool)
+		walk  func(node globalTypes.ProfitAnalysisObject, depth uint)
+	)
+	walk = func(node globalTypes.ProfitAnalysisObject, depth uint) {
+		if node.Pruned != "" && !seen[node.Item_id] {
+			seen[node.Item_id] = true
+			nodes = append(nodes, globalTypes.PrunedNode{
+				Item_id:   node.Item_id,
+				Item_name: node.Item_name,
+				Depth:     depth,
+				Reason:    node.Pruned,
+			})
+		}
+		for _, option := range node.Recipe_options {
+			for _, reagent := range option.Prices {
+				walk(reagent, depth+1)
+			}
+		}
+	}
+	walk(price_data, 0)
+	return nodes
+}
@@ -0,0 +1,65 @@
+package wow_crafting_profits
+
+import (
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/util"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestPruneReason(t *testing.T) {
+	cpc := &WoWCpCRunner{
+		maxDepth:      2,
+		rawMaterials:  util.SetFromSlice([]globalTypes.ItemID{7}),
+		minCraftValue: 100,
+	}
+	listed := globalTypes.AHItemPriceObject{Total_sales: 5, Price: 50}
+
+	tests := []struct {
+		name      string
+		item_id   globalTypes.ItemID
+		depth     uint
+		ah        globalTypes.AHItemPriceObject
+		wantPrune bool
+	}{
+		{name: "analyzed item is never pruned", item_id: 7, depth: 0, ah: listed},
+		{name: "raw material", item_id: 7, depth: 1, wantPrune: true},
+		{name: "within max depth", item_id: 1, depth: 1},
+		{name: "at max depth", item_id: 1, depth: 2, wantPrune: true},
+		{name: "below min value", item_id: 1, depth: 1, ah: listed, wantPrune: true},
+		{name: "above min value", item_id: 1, depth: 1, ah: globalTypes.AHItemPriceObject{Total_sales: 5, Price: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cpc.pruneReason(tt.item_id, tt.depth, tt.ah)
+			if (got != "") != tt.wantPrune {
+				t.Errorf("pruneReason() = %q, want pruned %v", got, tt.wantPrune)
+			}
+		})
+	}
+}
+
+func TestPrunedNodes(t *testing.T) {
+	herb := globalTypes.ProfitAnalysisObject{Item_id: 3, Item_name: "Herb", Pruned: "raw material"}
+	price_data := globalTypes.ProfitAnalysisObject{
+		Item_id: 1,
+		Recipe_options: []globalTypes.RecipeOption{
+			{Prices: []globalTypes.ProfitAnalysisObject{
+				herb,
+				{Item_id: 2, Recipe_options: []globalTypes.RecipeOption{
+					{Prices: []globalTypes.ProfitAnalysisObject{{Item_id: 4, Pruned: "deeper than max depth 2"}}},
+				}},
+			}},
+			{Prices: []globalTypes.ProfitAnalysisObject{herb}},
+		},
+	}
+
+	got := prunedNodes(price_data)
+	if len(got) != 2 {
+		t.Fatalf("prunedNodes() = %+v, want 2 nodes", got)
+	}
+	if got[0].Item_id != 3 || got[0].Depth != 1 || got[1].Item_id != 4 || got[1].Depth != 2 {
+		t.Errorf("prunedNodes() = %+v, want herb at depth 1 and item 4 at depth 2", got)
+	}
+}
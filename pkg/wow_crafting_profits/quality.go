@@ -0,0 +1,111 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+// Find the crafting quality tier of an auction from its modifiers, 0 when it carries none
+func auctionQualityTier(auction BlizzardApi.Auction, modifier_types []int) uint {
+	for _, modifier := range auction.Item.Modifiers {
+		if modifier.Value > 0 && slices.Contains(modifier_types, modifier.Type) {
+			return uint(modifier.Value)
+		}
+	}
+	return 0
+}
+
+// Find every tier of a reagent and the tier of item_id itself, tiers is nil when the reagent has no quality tiers
+func reagentQualityTiers(item_id globalTypes.ItemID, reagent_tiers [][]uint) (tiers []globalTypes.ItemID, tier uint) {
+	for _, group := range reagent_tiers {
+		if loc := slices.Index(group, item_id); loc != -1 {
+			return group, uint(loc + 1)
+		}
+	}
+	return nil, 0
+}
+
+/*
+Price every crafting quality tier of an item. Reagent tiers are separate items and are priced by item id,
+other items are split by the quality modifier on each auction. Also returns the tier of the item itself,
+which is only known for reagents.
+*/
+func (cpc *WoWCpCRunner) getQualityPrices(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, item_id globalTypes.ItemID, quantity float64) (uint, []globalTypes.QualityTierPrice) {
+	quality := cpc.staticSources.GetCraftingQuality()
+
+	if tiers, item_tier := reagentQualityTiers(item_id, quality.Reagent_tiers); tiers != nil {
+		prices := make([]globalTypes.QualityTierPrice, 0, len(tiers))
+		for i, tier_id := range tiers {
+			prices = append(prices, globalTypes.QualityTierPrice{
+				Tier:    uint(i + 1),
+				Item_id: tier_id,
+				Ah:      cpc.getAHPrice(ctx, region, realm_id, tier_id, 0, quantity),
+			})
+		}
+		return item_tier, prices
+	}
+
+	if len(quality.Quality_modifier_types) == 0 {
+		return 0, nil
+	}
+	by_tier := make(map[uint][]BlizzardApi.Auction)
+	for _, auction := range cpc.itemAuctions(item_id) {
+		if tier := auctionQualityTier(auction, quality.Quality_modifier_types); tier != 0 {
+			by_tier[tier] = append(by_tier[tier], auction)
+		}
+	}
+	prices := make([]globalTypes.QualityTierPrice, 0, len(by_tier))
+	for _, tier := range slices.Sorted(maps.Keys(by_tier)) {
+		prices = append(prices, globalTypes.QualityTierPrice{
+			Tier:    tier,
+			Item_id: item_id,
+			Ah: cpc.priceAuctions(ctx, PriceRequest{
+				Item_id:  item_id,
+				Region:   region,
+				Realm_id: realm_id,
+				Quantity: quantity,
+			}, by_tier[tier]),
+		})
+	}
+	return 0, prices
+}
+
+/*
+Choose which quality tier of a reagent a recipe crafts with. The run can ask for a tier for every recipe
+or for single recipes, otherwise the cheapest tier on the auction house is used.
+Reagents without tiers, or without the requested tier, are used as the recipe lists them.
+*/
+func (cpc *WoWCpCRunner) chooseReagentTier(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, recipe_id uint, item_id globalTypes.ItemID, quantity float64) globalTypes.ItemID {
+	tiers, _ := reagentQualityTiers(item_id, cpc.staticSources.GetCraftingQuality().Reagent_tiers)
+	if tiers == nil {
+		return item_id
+	}
+
+	wanted := cpc.reagentQuality
+	if tier, present := cpc.recipeReagentQuality[recipe_id]; present {
+		wanted = tier
+	}
+	if wanted > 0 {
+		if int(wanted) <= len(tiers) {
+			return tiers[wanted-1]
+		}
+		cpc.Logger.Infof("Reagent %d has no quality tier %d, using it as listed", item_id, wanted)
+		return item_id
+	}
+
+	chosen := item_id
+	best := math.Inf(1)
+	for _, tier_id := range tiers {
+		price := cpc.getAHPrice(ctx, region, realm_id, tier_id, 0, quantity)
+		if price.Total_sales > 0 && price.Price < best {
+			best = price.Price
+			chosen = tier_id
+		}
+	}
+	return chosen
+}
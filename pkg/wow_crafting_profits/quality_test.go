@@ -0,0 +1,164 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+const quality_test_auctions = `[
+	{"id": 1, "item": {"id": 100}, "quantity": 10, "unit_price": 300},
+	{"id": 2, "item": {"id": 101}, "quantity": 10, "unit_price": 200},
+	{"id": 3, "item": {"id": 102}, "quantity": 10, "unit_price": 500},
+	{"id": 4, "item": {"id": 500, "modifiers": [{"type": 9, "value": 50}, {"type": 38, "value": 2}]}, "quantity": 1, "buyout": 1000},
+	{"id": 5, "item": {"id": 500, "modifiers": [{"type": 38, "value": 5}]}, "quantity": 1, "buyout": 4000},
+	{"id": 6, "item": {"id": 500}, "quantity": 1, "buyout": 700}
+]`
+
+func newQualityTestRunner(t *testing.T) *WoWCpCRunner {
+	t.Helper()
+
+	dir := t.TempDir()
+	quality := `{"Quality_modifier_types": [38], "Reagent_tiers": [[100, 101, 102]]}`
+	if err := os.WriteFile(filepath.Join(dir, "crafting-quality.json"), []byte(quality), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var auctions []BlizzardApi.Auction
+	if err := json.Unmarshal([]byte(quality_test_auctions), &auctions); err != nil {
+		t.Fatal(err)
+	}
+
+	cpc := &WoWCpCRunner{
+		Logger:          cpclog.NewCpCLog(cpclog.GetLevel("error")),
+		indexedAuctions: make(map[globalTypes.ItemID][]BlizzardApi.Auction),
+	}
+	cpc.staticSources.RootDirectory = dir
+	for _, auction := range auctions {
+		cpc.indexedAuctions[auction.Item.Id] = append(cpc.indexedAuctions[auction.Item.Id], auction)
+	}
+	return cpc
+}
+
+func TestAuctionQualityTier(t *testing.T) {
+	cpc := newQualityTestRunner(t)
+	want := []uint{2, 5, 0}
+	for i, auction := range cpc.indexedAuctions[500] {
+		if got := auctionQualityTier(auction, []int{38}); got != want[i] {
+			t.Errorf("auctionQualityTier(auction %d) = %d, want %d", auction.Id, got, want[i])
+		}
+	}
+}
+
+func TestGetQualityPrices(t *testing.T) {
+	cpc := newQualityTestRunner(t)
+
+	tests := []struct {
+		name       string
+		item_id    globalTypes.ItemID
+		wantTier   uint
+		wantTiers  []uint
+		wantPrices []float64
+	}{
+		{name: "reagent tiers", item_id: 101, wantTier: 2, wantTiers: []uint{1, 2, 3}, wantPrices: []float64{300, 200, 500}},
+		{name: "crafted item modifiers", item_id: 500, wantTiers: []uint{2, 5}, wantPrices: []float64{1000, 4000}},
+		{name: "no quality", item_id: 999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, prices := cpc.getQualityPrices(context.Background(), "us", 0, tt.item_id, 1)
+			if tier != tt.wantTier {
+				t.Errorf("getQualityPrices() tier = %d, want %d", tier, tt.wantTier)
+			}
+			if len(prices) != len(tt.wantTiers) {
+				t.Fatalf("getQualityPrices() returned %d tiers, want %d", len(prices), len(tt.wantTiers))
+			}
+			for i, price := range prices {
+				if price.Tier != tt.wantTiers[i] || price.Ah.Price != tt.wantPrices[i] {
+					t.Errorf("getQualityPrices()[%d] = tier %d at %v, want tier %d at %v", i, price.Tier, price.Ah.Price, tt.wantTiers[i], tt.wantPrices[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChooseReagentTier(t *testing.T) {
+	tests := []struct {
+		name           string
+		reagent        globalTypes.ItemID
+		run_quality    uint
+		recipe_quality map[uint]uint
+		want           globalTypes.ItemID
+	}{
+		{name: "cheapest tier", reagent: 100, want: 101},
+		{name: "run wide tier", reagent: 100, run_quality: 3, want: 102},
+		{name: "recipe tier wins", reagent: 100, run_quality: 3, recipe_quality: map[uint]uint{7: 1}, want: 100},
+		{name: "tier out of range", reagent: 101, run_quality: 4, want: 101},
+		{name: "reagent without tiers", reagent: 500, run_quality: 2, want: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpc := newQualityTestRunner(t)
+			cpc.reagentQuality = tt.run_quality
+			cpc.recipeReagentQuality = tt.recipe_quality
+			if got := cpc.chooseReagentTier(context.Background(), "us", 0, 7, tt.reagent, 5); got != tt.want {
+				t.Errorf("chooseReagentTier() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShippedReagentTiers(t *testing.T) {
+	tests := []struct {
+		name        string
+		reagent     globalTypes.ItemID
+		run_quality uint
+		want        globalTypes.ItemID
+	}{
+		{name: "Hochenblume second tier", reagent: 191460, run_quality: 2, want: 191461},
+		{name: "Draconium Ore third tier", reagent: 189143, run_quality: 3, want: 190311},
+		{name: "Khaz'gorite Ore first tier", reagent: 190314, run_quality: 1, want: 190312},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpc := &WoWCpCRunner{
+				Logger:         cpclog.NewCpCLog(cpclog.GetLevel("error")),
+				reagentQuality: tt.run_quality,
+			}
+			cpc.staticSources.RootDirectory = "../../static_files"
+			if got := cpc.chooseReagentTier(context.Background(), "us", 0, 7, tt.reagent, 5); got != tt.want {
+				t.Errorf("chooseReagentTier() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShippedQualityModifiers(t *testing.T) {
+	var auctions []BlizzardApi.Auction
+	if err := json.Unmarshal([]byte(quality_test_auctions), &auctions); err != nil {
+		t.Fatal(err)
+	}
+	cpc := &WoWCpCRunner{
+		Logger:          cpclog.NewCpCLog(cpclog.GetLevel("error")),
+		indexedAuctions: make(map[globalTypes.ItemID][]BlizzardApi.Auction),
+	}
+	cpc.staticSources.RootDirectory = "../../static_files"
+	for _, auction := range auctions {
+		cpc.indexedAuctions[auction.Item.Id] = append(cpc.indexedAuctions[auction.Item.Id], auction)
+	}
+
+	// The crafted item's listings carry their tier in the crafting quality modifier, the timewalker level is ignored
+	_, prices := cpc.getQualityPrices(context.Background(), "us", 0, 500, 1)
+	if len(prices) != 2 || prices[0].Tier != 2 || prices[0].Ah.Price != 1000 || prices[1].Tier != 5 || prices[1].Ah.Price != 4000 {
+		t.Errorf("getQualityPrices() = %+v, want tier 2 at 1000 and tier 5 at 4000", prices)
+	}
+}
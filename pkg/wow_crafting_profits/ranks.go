@@ -0,0 +1,53 @@
+package wow_crafting_profits
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/static_sources"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+/*
+Find the crafted item level of a recipe's rank. A per recipe entry in the rank mappings wins, then the
+rank Blizzard lists for the recipe, and last the recipe's position among the item's recipes ordered by id.
+Only items with more than one recipe have ranks, 0 means the recipe has no rank.
+Also returns which rule decided the rank, for explain mode.
+*/
+func recipeRankLevel(recipe BlizzardApi.Recipe, recipe_ids []uint, rankings static_sources.RankMappingsCache) (uint, string) {
+	ids := slices.Compact(slices.Sorted(slices.Values(recipe_ids)))
+	if len(ids) <= 1 {
+		return 0, "only recipe for the item"
+	}
+
+	rank_index, found := rankings.Recipe_ranks[fmt.Sprint(recipe.Id)]
+	source := "recipe_ranks mapping"
+	if !found {
+		position := slices.Index(ids, recipe.Id)
+		source = fmt.Sprintf("position %d among recipe ids", position+1)
+		if recipe.Rank > 0 {
+			position = int(recipe.Rank) - 1
+			source = fmt.Sprintf("recipe rank %d", recipe.Rank)
+		}
+		if position < 0 || position >= len(rankings.Rank_mapping) {
+			return 0, source + " is outside rank_mapping"
+		}
+		rank_index = rankings.Rank_mapping[position]
+	}
+	if int(rank_index) >= len(rankings.Available_levels) {
+		return 0, source + " is outside available_levels"
+	}
+	return rankings.Available_levels[rank_index], source
+}
+
+// Order recipe options by rank and then recipe id, so output is the same from run to run
+func sortRecipeOptions(options []globalTypes.RecipeOption) {
+	slices.SortStableFunc(options, func(a, b globalTypes.RecipeOption) int {
+		if c := cmp.Compare(a.Rank, b.Rank); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Recipe.Recipe_id, b.Recipe.Recipe_id)
+	})
+}
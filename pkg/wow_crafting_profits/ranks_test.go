@@ -0,0 +1,60 @@
+package wow_crafting_profits
+
+import (
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/static_sources"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+func TestRecipeRankLevel(t *testing.T) {
+	rankings := static_sources.RankMappingsCache{
+		Available_levels: []uint{190, 210, 225},
+		Rank_mapping:     []uint{0, 1, 2},
+		Recipe_ranks:     map[string]uint{"40": 0},
+	}
+
+	tests := []struct {
+		name       string
+		recipe     BlizzardApi.Recipe
+		recipe_ids []uint
+		want       uint
+	}{
+		{name: "single recipe has no rank", recipe: BlizzardApi.Recipe{Id: 10}, recipe_ids: []uint{10}},
+		{name: "position among sorted ids", recipe: BlizzardApi.Recipe{Id: 20}, recipe_ids: []uint{30, 10, 20}, want: 210},
+		{name: "discovery order does not matter", recipe: BlizzardApi.Recipe{Id: 20}, recipe_ids: []uint{20, 30, 10}, want: 210},
+		{name: "duplicate ids are ignored", recipe: BlizzardApi.Recipe{Id: 30}, recipe_ids: []uint{10, 10, 30}, want: 210},
+		{name: "rank from the recipe", recipe: BlizzardApi.Recipe{Id: 10, Rank: 3}, recipe_ids: []uint{10, 20, 30}, want: 225},
+		{name: "per recipe mapping wins", recipe: BlizzardApi.Recipe{Id: 40, Rank: 3}, recipe_ids: []uint{10, 40}, want: 190},
+		{name: "rank beyond the mapping", recipe: BlizzardApi.Recipe{Id: 10, Rank: 5}, recipe_ids: []uint{10, 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := recipeRankLevel(tt.recipe, tt.recipe_ids, rankings); got != tt.want {
+				t.Errorf("recipeRankLevel() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeShoppingListOrder(t *testing.T) {
+	merged := mergeShoppingList([]globalTypes.ShoppingList{
+		{Id: 3, Quantity: 1},
+		{Id: 1, Quantity: 2},
+		{Id: 3, Quantity: 4},
+		{Id: 2, Quantity: 1},
+	})
+	if len(merged) != 3 {
+		t.Fatalf("mergeShoppingList() returned %d entries, want 3", len(merged))
+	}
+	for i, id := range []globalTypes.ItemID{1, 2, 3} {
+		if merged[i].Id != id {
+			t.Errorf("mergeShoppingList()[%d].Id = %d, want %d", i, merged[i].Id, id)
+		}
+	}
+	if merged[2].Quantity != 5 {
+		t.Errorf("merged quantity = %v, want 5", merged[2].Quantity)
+	}
+}
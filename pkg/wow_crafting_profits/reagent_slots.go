@@ -0,0 +1,126 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/static_sources"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+/*
+Describe the modified crafting slots of a recipe, pricing every reagent that fits each slot and choosing
+the reagent to craft with. The run's choice for a slot type wins, otherwise required slots use their
+cheapest reagent on the auction house and optional slots are left empty.
+*/
+func (cpc *WoWCpCRunner) recipeSlots(ctx context.Context, region globalTypes.RegionCode, realm_id globalTypes.ConnectedRealmID, recipe BlizzardApi.Recipe, crafts_required float64) []globalTypes.RecipeSlot {
+	if len(recipe.Modified_crafting_slots) == 0 {
+		return nil
+	}
+	slot_data := *cpc.staticSources.GetReagentSlots()
+
+	modified_slots := slices.Clone(recipe.Modified_crafting_slots)
+	sort.SliceStable(modified_slots, func(i, j int) bool {
+		return modified_slots[i].Display_order < modified_slots[j].Display_order
+	})
+
+	slots := make([]globalTypes.RecipeSlot, 0, len(modified_slots))
+	for _, modified_slot := range modified_slots {
+		slot_type := uint(modified_slot.Slot_type.Id)
+		static_slot, known := slot_data[fmt.Sprint(slot_type)]
+		if !known {
+			static_slot = cpc.apiReagentSlot(ctx, region, slot_type)
+		}
+		slot := globalTypes.RecipeSlot{
+			Slot_type: slot_type,
+			Name:      modified_slot.Slot_type.Name,
+			Required:  static_slot.Required,
+			Quantity:  static_slot.Quantity,
+			Options:   make([]globalTypes.SlotReagentPrice, 0, len(static_slot.Reagents)),
+		}
+		if slot.Name == "" {
+			slot.Name = static_slot.Name
+		}
+		if slot.Quantity == 0 {
+			slot.Quantity = 1
+		}
+
+		cheapest := math.Inf(1)
+		for _, reagent_id := range static_slot.Reagents {
+			price := cpc.getAHPrice(ctx, region, realm_id, reagent_id, 0, crafts_required*float64(slot.Quantity))
+			slot.Options = append(slot.Options, globalTypes.SlotReagentPrice{Item_id: reagent_id, Ah: price})
+			if slot.Required && price.Total_sales > 0 && price.Price < cheapest {
+				cheapest = price.Price
+				slot.Chosen = reagent_id
+			}
+		}
+		if chosen, present := cpc.slotReagents[slot_type]; present {
+			slot.Chosen = chosen
+		}
+		if slot.Required && slot.Chosen == 0 {
+			cpc.Logger.Infof("Required slot %s (%d) of recipe %d has no reagent, costs will be understated", slot.Name, slot_type, recipe.Id)
+		}
+
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+/*
+Describe a slot type the static reagent slots file does not know from the Blizzard API. The API names the slot
+and the reagent categories that fit it, and the reagents of each category are found with an item search.
+The API does not say whether a slot must be filled, so these slots are optional unless the run chooses a reagent.
+*/
+func (cpc *WoWCpCRunner) apiReagentSlot(ctx context.Context, region globalTypes.RegionCode, slot_type uint) static_sources.ReagentSlot {
+	slot_type_detail, err := cpc.Helper.GetBlizReagentSlotType(ctx, slot_type, region)
+	if err != nil {
+		cpc.Logger.Debugf("Could not fetch reagent slot type %d: %v", slot_type, err)
+		return static_sources.ReagentSlot{}
+	}
+	slot := static_sources.ReagentSlot{Name: slot_type_detail.Description}
+	for _, category := range slot_type_detail.Compatible_categories {
+		reagents, err := cpc.Helper.GetModifiedCraftingCategoryItems(ctx, uint(category.Id), region)
+		if err != nil {
+			cpc.Logger.Debugf("Could not find the reagents of category %s (%d): %v", category.Name, category.Id, err)
+			continue
+		}
+		for _, reagent_id := range reagents {
+			if !slices.Contains(slot.Reagents, reagent_id) {
+				slot.Reagents = append(slot.Reagents, reagent_id)
+			}
+		}
+	}
+	if len(slot.Reagents) == 0 {
+		cpc.Logger.Infof("No reagents are known for slot type %s (%d)", slot_type_detail.Description, slot_type)
+	}
+	return slot
+}
+
+func generateSlotsOutputFormat(slots []globalTypes.RecipeSlot) []globalTypes.OutputFormatSlot {
+	if len(slots) == 0 {
+		return nil
+	}
+	slots_output := make([]globalTypes.OutputFormatSlot, 0, len(slots))
+	for _, slot := range slots {
+		slot_output := globalTypes.OutputFormatSlot{
+			Id:       slot.Slot_type,
+			Name:     slot.Name,
+			Required: slot.Required,
+			Quantity: slot.Quantity,
+			Chosen:   slot.Chosen,
+			Options:  make([]globalTypes.OutputFormatSlotOption, 0, len(slot.Options)),
+		}
+		for _, option := range slot.Options {
+			slot_output.Options = append(slot_output.Options, globalTypes.OutputFormatSlotOption{
+				Id: option.Item_id,
+				Ah: outputFormatPrice(option.Ah),
+			})
+		}
+		slots_output = append(slots_output, slot_output)
+	}
+	return slots_output
+}
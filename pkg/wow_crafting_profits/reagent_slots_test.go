@@ -0,0 +1,101 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+func TestRecipeSlots(t *testing.T) {
+	dir := t.TempDir()
+	slot_data := `{
+		"10": {"Name": "Spark", "Required": true, "Reagents": [301, 302]},
+		"20": {"Name": "Finishing", "Quantity": 2, "Reagents": [401]}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "reagent-slots.json"), []byte(slot_data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var recipe BlizzardApi.Recipe
+	recipe_data := `{"id": 7, "modified_crafting_slots": [
+		{"slot_type": {"id": 20}, "display_order": 1},
+		{"slot_type": {"id": 10}, "display_order": 0}
+	]}`
+	if err := json.Unmarshal([]byte(recipe_data), &recipe); err != nil {
+		t.Fatal(err)
+	}
+
+	var expensive, cheap, finishing BlizzardApi.Auction
+	expensive.Item.Id, expensive.Quantity, expensive.Unit_price = 301, 5, 900
+	cheap.Item.Id, cheap.Quantity, cheap.Unit_price = 302, 5, 600
+	finishing.Item.Id, finishing.Quantity, finishing.Unit_price = 401, 5, 50
+
+	tests := []struct {
+		name          string
+		slot_reagents map[uint]globalTypes.ItemID
+		want          []globalTypes.ItemID
+	}{
+		{name: "defaults", want: []globalTypes.ItemID{302, 0}},
+		{name: "chosen", slot_reagents: map[uint]globalTypes.ItemID{10: 301, 20: 401}, want: []globalTypes.ItemID{301, 401}},
+		{name: "required slot left empty", slot_reagents: map[uint]globalTypes.ItemID{10: 0}, want: []globalTypes.ItemID{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpc := &WoWCpCRunner{
+				Logger: cpclog.NewCpCLog(cpclog.GetLevel("error")),
+				indexedAuctions: map[globalTypes.ItemID][]BlizzardApi.Auction{
+					301: {expensive}, 302: {cheap}, 401: {finishing},
+				},
+				slotReagents: tt.slot_reagents,
+			}
+			cpc.staticSources.RootDirectory = dir
+
+			slots := cpc.recipeSlots(context.Background(), "us", 0, recipe, 3)
+			if len(slots) != 2 {
+				t.Fatalf("recipeSlots() returned %d slots, want 2", len(slots))
+			}
+			if slots[0].Name != "Spark" || !slots[0].Required || len(slots[0].Options) != 2 {
+				t.Errorf("recipeSlots()[0] = %+v, want the required Spark slot with 2 options", slots[0])
+			}
+			if slots[1].Quantity != 2 || slots[1].Options[0].Ah.Required != 6 {
+				t.Errorf("recipeSlots()[1] quantity %d priced for %v, want 2 priced for 6", slots[1].Quantity, slots[1].Options[0].Ah.Required)
+			}
+			for i, slot := range slots {
+				if slot.Chosen != tt.want[i] {
+					t.Errorf("recipeSlots()[%d].Chosen = %d, want %d", i, slot.Chosen, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShippedReagentSlots(t *testing.T) {
+	cpc := offlineTestRunner(t)
+	cpc.indexAuctions(cpc.Auctions)
+
+	var recipe BlizzardApi.Recipe
+	if err := json.Unmarshal([]byte(`{"id": 5001, "modified_crafting_slots": [{"slot_type": {"id": 990}, "display_order": 0}]}`), &recipe); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, known := (*cpc.staticSources.GetReagentSlots())["990"]; known {
+		t.Fatal("shipped reagent slots already describe slot type 990, the test needs a slot only the API knows")
+	}
+	slots := cpc.recipeSlots(context.Background(), "us", 57, recipe, 1)
+	if len(slots) != 1 {
+		t.Fatalf("recipeSlots() returned %d slots, want 1", len(slots))
+	}
+	if slots[0].Name != "Test Finishing Reagent" || slots[0].Quantity != 1 || slots[0].Required {
+		t.Errorf("recipeSlots()[0] = %+v, want the optional API named slot", slots[0])
+	}
+	if options := slots[0].Options; len(options) != 1 || options[0].Item_id != 2001 || options[0].Ah.Total_sales == 0 {
+		t.Errorf("recipeSlots()[0] options = %+v, want the priced reagent of the slot's category", options)
+	}
+}
@@ -0,0 +1,198 @@
+package wow_crafting_profits
+
+import (
+	"math"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+// Quantities this close under a whole number of crafts are float error, not a reason to craft again
+const craft_rounding_tolerance float64 = 1e-9
+
+/*
+Everything needed to make an item: what to take from the bags, what to buy, and which intermediates to craft in which order.
+Reagents are always added before the craft that uses them, so steps come out in crafting order.
+*/
+type shoppingPlan struct {
+	purchases     []globalTypes.ShoppingList
+	steps         []globalTypes.CraftStep
+	intermediates globalTypes.IntermediatePolicy // Empty follows the make vs. buy analysis
+
+	on_hand *globalTypes.RunConfiguration // Inventory items are taken from before they are crafted or bought, nil for none
+	used    []globalTypes.ReagentUse
+}
+
+/*
+Add an item to the plan the way the make vs. buy analysis says it should be acquired,
+crafting it, converting it through a recipe cycle or buying it.
+Whatever the inventory holds of the item is used first, so owned intermediates are never crafted again.
+The plan's intermediate policy can override the analysis for items that have a recipe.
+*/
+func (plan *shoppingPlan) acquire(node globalTypes.OutputFormatObject, needed float64) {
+	needed = plan.fromInventory(node.Id, node.Name, needed)
+	if needed <= craft_rounding_tolerance {
+		return
+	}
+	if node.Optimal != nil {
+		switch plan.intermediateMethod(node) {
+		case globalTypes.ACQUIRE_CRAFT:
+			if recipe, found := findOutputRecipe(node, node.Optimal.Recipe_id); found {
+				plan.craft(node, recipe, needed)
+				return
+			}
+		case globalTypes.ACQUIRE_CONVERT:
+			if node.Conversion != nil {
+				plan.convert(node, needed)
+				return
+			}
+		}
+	}
+	plan.buy(node, needed)
+}
+
+/*
+Craft enough of an item with a recipe, rounding up to whole crafts of the recipe's expected yield.
+Reagents are bought or crafted for every craft made, not just the share of them that is needed.
+*/
+func (plan *shoppingPlan) craft(node globalTypes.OutputFormatObject, recipe globalTypes.OutputFormatRecipe, needed float64) {
+	yield := recipeYield(recipe)
+	crafts := wholeCrafts(needed, yield)
+	for _, part := range recipe.Parts {
+		plan.acquire(part, part.Required*float64(crafts))
+	}
+	plan.steps = append(plan.steps, globalTypes.CraftStep{
+		Id:        node.Id,
+		Name:      node.Name,
+		Recipe_id: recipe.Id,
+		Crafts:    crafts,
+		Quantity:  float64(crafts) * yield,
+	})
+}
+
+func (plan *shoppingPlan) intermediateMethod(node globalTypes.OutputFormatObject) globalTypes.AcquisitionMethod {
+	if node.Optimal.Recipe_id == 0 {
+		return node.Optimal.Method
+	}
+	switch plan.intermediates {
+	case globalTypes.INTERMEDIATES_CRAFT:
+		return globalTypes.ACQUIRE_CRAFT
+	case globalTypes.INTERMEDIATES_BUY:
+		if node.Ah.Sales > 0 || node.Vendor > 0 {
+			return globalTypes.ACQUIRE_BUY_AH
+		}
+	}
+	return node.Optimal.Method
+}
+
+// Buy the source of an item's conversion chain rather than the item itself, after using any of the source in the inventory
+func (plan *shoppingPlan) convert(node globalTypes.OutputFormatObject, needed float64) {
+	source_needed := plan.fromInventory(node.Conversion.Source_id, node.Conversion.Source_name, needed*node.Conversion.Ratio)
+	if source_needed <= craft_rounding_tolerance {
+		return
+	}
+	plan.purchases = append(plan.purchases, globalTypes.ShoppingList{
+		Id:       node.Conversion.Source_id,
+		Name:     node.Conversion.Source_name,
+		Quantity: source_needed,
+		Cost: globalTypes.ShoppingListCost{
+			Ah:     node.Conversion.Ah,
+			Vendor: node.Conversion.Vendor,
+		},
+	})
+}
+
+func (plan *shoppingPlan) buy(node globalTypes.OutputFormatObject, needed float64) {
+	plan.purchases = append(plan.purchases, globalTypes.ShoppingList{
+		Id:       node.Id,
+		Name:     node.Name,
+		Quantity: needed,
+		Cost: globalTypes.ShoppingListCost{
+			Ah:            node.Ah,
+			Vendor:        node.Vendor,
+			Vendor_source: node.Vendor_source,
+		},
+	})
+}
+
+// Take as much of an item as the inventory has toward what is needed, returning how much is still needed
+func (plan *shoppingPlan) fromInventory(item_id globalTypes.ItemID, name globalTypes.ItemName, needed float64) float64 {
+	if plan.on_hand == nil || needed <= 0 {
+		return needed
+	}
+	if taken := plan.on_hand.UseInventory(item_id, needed); taken > 0 {
+		plan.used = append(plan.used, globalTypes.ReagentUse{Id: item_id, Name: name, Quantity: taken})
+		needed -= taken
+	}
+	return needed
+}
+
+// Everything to buy, one entry per item
+func (plan shoppingPlan) shoppingList() []globalTypes.ShoppingList {
+	return mergeShoppingList(plan.purchases)
+}
+
+/*
+The crafts to make, one step per item and recipe in the order they are first needed.
+An intermediate used by several reagents is rounded up to whole crafts for each of them.
+*/
+func (plan shoppingPlan) craftSteps() []globalTypes.CraftStep {
+	type stepKey struct {
+		item_id   globalTypes.ItemID
+		recipe_id uint
+	}
+	positions := make(map[stepKey]int)
+	steps := make([]globalTypes.CraftStep, 0, len(plan.steps))
+	for _, step := range plan.steps {
+		key := stepKey{step.Id, step.Recipe_id}
+		if position, present := positions[key]; present {
+			steps[position].Crafts += step.Crafts
+			steps[position].Quantity += step.Quantity
+			continue
+		}
+		positions[key] = len(steps)
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Everything taken from the inventory, one entry per item in the order it was first used
+func (plan shoppingPlan) inventoryUsed() []globalTypes.ReagentUse {
+	positions := make(map[globalTypes.ItemID]int)
+	used := make([]globalTypes.ReagentUse, 0, len(plan.used))
+	for _, use := range plan.used {
+		if position, present := positions[use.Id]; present {
+			used[position].Quantity += use.Quantity
+			continue
+		}
+		positions[use.Id] = len(used)
+		used = append(used, use)
+	}
+	return used
+}
+
+func findOutputRecipe(node globalTypes.OutputFormatObject, recipe_id uint) (globalTypes.OutputFormatRecipe, bool) {
+	for _, recipe := range node.Recipes {
+		if recipe.Id == recipe_id {
+			return recipe, true
+		}
+	}
+	return globalTypes.OutputFormatRecipe{}, false
+}
+
+// Average items made by a craft, including multicraft when the crafter's stats are known
+func recipeYield(recipe globalTypes.OutputFormatRecipe) float64 {
+	if recipe.Output.Expected > 0 {
+		return recipe.Output.Expected
+	}
+	if recipe.Output.Value > 0 {
+		return recipe.Output.Value
+	}
+	return 1
+}
+
+func wholeCrafts(needed float64, yield float64) uint {
+	if needed <= 0 {
+		return 0
+	}
+	return uint(math.Ceil(needed/yield - craft_rounding_tolerance))
+}
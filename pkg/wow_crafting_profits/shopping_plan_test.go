@@ -0,0 +1,211 @@
+package wow_crafting_profits
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+/*
+Ten of item 1, each crafted from 2 of item 2 and 1 vendor bought item 4.
+Item 2 is made 5 at a time from 3 of item 3, and acquired however intermediate_method says.
+*/
+func shoppingPlanTestTree(intermediate_method globalTypes.AcquisitionMethod, expected_yield float64) globalTypes.OutputFormatObject {
+	herb := globalTypes.OutputFormatObject{Id: 3, Name: "Herb", Required: 3, Optimal: &globalTypes.OutputFormatAcquisition{Method: globalTypes.ACQUIRE_BUY_AH}}
+	intermediate := globalTypes.OutputFormatObject{
+		Id:       2,
+		Name:     "Extract",
+		Required: 2,
+		Optimal:  &globalTypes.OutputFormatAcquisition{Method: intermediate_method, Recipe_id: 20},
+		Recipes: []globalTypes.OutputFormatRecipe{
+			{Id: 20, Output: globalTypes.OutpoutFormatRecipeOutput{Value: 5}, Parts: []globalTypes.OutputFormatObject{herb}},
+		},
+	}
+	vial := globalTypes.OutputFormatObject{Id: 4, Name: "Vial", Required: 1, Vendor: 10, Optimal: &globalTypes.OutputFormatAcquisition{Method: globalTypes.ACQUIRE_BUY_VENDOR}}
+	return globalTypes.OutputFormatObject{
+		Id:       1,
+		Name:     "Potion",
+		Required: 10,
+		Optimal:  &globalTypes.OutputFormatAcquisition{Method: globalTypes.ACQUIRE_BUY_AH, Recipe_id: 10},
+		Recipes: []globalTypes.OutputFormatRecipe{
+			{Id: 10, Output: globalTypes.OutpoutFormatRecipeOutput{Value: 1, Expected: expected_yield}, Parts: []globalTypes.OutputFormatObject{intermediate, vial}},
+		},
+	}
+}
+
+func TestBuildOptimalPlan(t *testing.T) {
+	tests := []struct {
+		name          string
+		tree          globalTypes.OutputFormatObject
+		wantPurchases map[globalTypes.ItemID]float64
+		wantSteps     []globalTypes.CraftStep
+	}{
+		{
+			name:          "intermediate crafted five at a time",
+			tree:          shoppingPlanTestTree(globalTypes.ACQUIRE_CRAFT, 0),
+			wantPurchases: map[globalTypes.ItemID]float64{3: 12, 4: 10},
+			wantSteps: []globalTypes.CraftStep{
+				{Id: 2, Name: "Extract", Recipe_id: 20, Crafts: 4, Quantity: 20},
+				{Id: 1, Name: "Potion", Recipe_id: 10, Crafts: 10, Quantity: 10},
+			},
+		},
+		{
+			name:          "intermediate bought",
+			tree:          shoppingPlanTestTree(globalTypes.ACQUIRE_BUY_AH, 0),
+			wantPurchases: map[globalTypes.ItemID]float64{2: 20, 4: 10},
+			wantSteps: []globalTypes.CraftStep{
+				{Id: 1, Name: "Potion", Recipe_id: 10, Crafts: 10, Quantity: 10},
+			},
+		},
+		{
+			name:          "multicraft rounds up to whole crafts",
+			tree:          shoppingPlanTestTree(globalTypes.ACQUIRE_CRAFT, 3),
+			wantPurchases: map[globalTypes.ItemID]float64{3: 6, 4: 4},
+			wantSteps: []globalTypes.CraftStep{
+				{Id: 2, Name: "Extract", Recipe_id: 20, Crafts: 2, Quantity: 10},
+				{Id: 1, Name: "Potion", Recipe_id: 10, Crafts: 4, Quantity: 12},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := buildOptimalPlan(tt.tree, shoppingPlan{})
+			purchases := make(map[globalTypes.ItemID]float64)
+			for _, li := range plan.shoppingList() {
+				purchases[li.Id] = li.Quantity
+			}
+			if !reflect.DeepEqual(purchases, tt.wantPurchases) {
+				t.Errorf("shoppingList() = %v, want %v", purchases, tt.wantPurchases)
+			}
+			if steps := plan.craftSteps(); !reflect.DeepEqual(steps, tt.wantSteps) {
+				t.Errorf("craftSteps() = %+v, want %+v", steps, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestCraftStepsMerge(t *testing.T) {
+	plan := shoppingPlan{steps: []globalTypes.CraftStep{
+		{Id: 2, Recipe_id: 20, Crafts: 1, Quantity: 5},
+		{Id: 3, Recipe_id: 30, Crafts: 1, Quantity: 1},
+		{Id: 2, Recipe_id: 20, Crafts: 2, Quantity: 10},
+	}}
+	want := []globalTypes.CraftStep{
+		{Id: 2, Recipe_id: 20, Crafts: 3, Quantity: 15},
+		{Id: 3, Recipe_id: 30, Crafts: 1, Quantity: 1},
+	}
+	if got := plan.craftSteps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("craftSteps() = %+v, want %+v", got, want)
+	}
+}
+
+func TestShoppingPlanIntermediatePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     globalTypes.AcquisitionMethod
+		sold       bool
+		policy     globalTypes.IntermediatePolicy
+		wantCrafts bool
+	}{
+		{name: "make vs buy crafts", method: globalTypes.ACQUIRE_CRAFT, sold: true, policy: globalTypes.INTERMEDIATES_MAKE_VS_BUY, wantCrafts: true},
+		{name: "buy policy buys a sold intermediate", method: globalTypes.ACQUIRE_CRAFT, sold: true, policy: globalTypes.INTERMEDIATES_BUY, wantCrafts: false},
+		{name: "buy policy crafts an intermediate that is not sold", method: globalTypes.ACQUIRE_CRAFT, sold: false, policy: globalTypes.INTERMEDIATES_BUY, wantCrafts: true},
+		{name: "craft policy crafts a bought intermediate", method: globalTypes.ACQUIRE_BUY_AH, sold: true, policy: globalTypes.INTERMEDIATES_CRAFT, wantCrafts: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intermediate := shoppingPlanTestTree(tt.method, 0).Recipes[0].Parts[0]
+			if tt.sold {
+				intermediate.Ah = globalTypes.OutputFormatPrice{Sales: 10, Low: 1}
+			}
+			plan := shoppingPlan{intermediates: tt.policy}
+			plan.acquire(intermediate, 10)
+			if crafted := len(plan.steps) > 0; crafted != tt.wantCrafts {
+				t.Errorf("acquire() crafted = %v, want %v, purchases %+v", crafted, tt.wantCrafts, plan.purchases)
+			}
+		})
+	}
+}
+
+func TestShoppingPlanInventory(t *testing.T) {
+	tests := []struct {
+		name          string
+		inventory     string
+		vials         float64
+		wantPurchases map[globalTypes.ItemID]float64
+		wantSteps     []globalTypes.CraftStep
+		wantUsed      []globalTypes.ReagentUse
+		wantLeft      map[globalTypes.ItemID]float64
+	}{
+		{
+			name:          "owned intermediates are used before crafting more",
+			inventory:     `[{"id":2,"quantity":12}]`,
+			vials:         1,
+			wantPurchases: map[globalTypes.ItemID]float64{3: 6, 4: 10},
+			wantSteps: []globalTypes.CraftStep{
+				{Id: 2, Name: "Extract", Recipe_id: 20, Crafts: 2, Quantity: 10},
+				{Id: 1, Name: "Potion", Recipe_id: 10, Crafts: 10, Quantity: 10},
+			},
+			wantUsed: []globalTypes.ReagentUse{{Id: 2, Name: "Extract", Quantity: 12}},
+			wantLeft: map[globalTypes.ItemID]float64{},
+		},
+		{
+			name:          "fractional reagents buy only what the inventory lacks",
+			inventory:     `[{"id":4,"quantity":2}]`,
+			vials:         0.35,
+			wantPurchases: map[globalTypes.ItemID]float64{3: 12, 4: 1.5},
+			wantSteps: []globalTypes.CraftStep{
+				{Id: 2, Name: "Extract", Recipe_id: 20, Crafts: 4, Quantity: 20},
+				{Id: 1, Name: "Potion", Recipe_id: 10, Crafts: 10, Quantity: 10},
+			},
+			wantUsed: []globalTypes.ReagentUse{{Id: 4, Name: "Vial", Quantity: 2}},
+			wantLeft: map[globalTypes.ItemID]float64{},
+		},
+		{
+			name:          "fractional use leaves the rest in the inventory",
+			inventory:     `[{"id":3,"quantity":20},{"id":4,"quantity":5}]`,
+			vials:         0.35,
+			wantPurchases: map[globalTypes.ItemID]float64{},
+			wantSteps: []globalTypes.CraftStep{
+				{Id: 2, Name: "Extract", Recipe_id: 20, Crafts: 4, Quantity: 20},
+				{Id: 1, Name: "Potion", Recipe_id: 10, Crafts: 10, Quantity: 10},
+			},
+			wantUsed: []globalTypes.ReagentUse{{Id: 3, Name: "Herb", Quantity: 12}, {Id: 4, Name: "Vial", Quantity: 3.5}},
+			wantLeft: map[globalTypes.ItemID]float64{3: 8, 4: 1.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var addon globalTypes.AddonData
+			if err := json.Unmarshal([]byte(`{"inventory":`+tt.inventory+`}`), &addon); err != nil {
+				t.Fatal(err)
+			}
+			on_hand := globalTypes.NewRunConfig(&addon, globalTypes.ItemSoftIdentity{}, 0)
+			tree := shoppingPlanTestTree(globalTypes.ACQUIRE_CRAFT, 0)
+			tree.Recipes[0].Parts[1].Required = tt.vials
+
+			plan := buildOptimalPlan(tree, shoppingPlan{on_hand: on_hand})
+			purchases := make(map[globalTypes.ItemID]float64)
+			for _, li := range plan.shoppingList() {
+				purchases[li.Id] = li.Quantity
+			}
+			if !reflect.DeepEqual(purchases, tt.wantPurchases) {
+				t.Errorf("shoppingList() = %v, want %v", purchases, tt.wantPurchases)
+			}
+			if steps := plan.craftSteps(); !reflect.DeepEqual(steps, tt.wantSteps) {
+				t.Errorf("craftSteps() = %+v, want %+v", steps, tt.wantSteps)
+			}
+			if used := plan.inventoryUsed(); !reflect.DeepEqual(used, tt.wantUsed) {
+				t.Errorf("inventoryUsed() = %+v, want %+v", used, tt.wantUsed)
+			}
+			if left := on_hand.RemainingInventory(); !reflect.DeepEqual(left, tt.wantLeft) {
+				t.Errorf("RemainingInventory() = %v, want %v", left, tt.wantLeft)
+			}
+		})
+	}
+}
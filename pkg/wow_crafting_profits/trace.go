@@ -0,0 +1,116 @@
+package wow_crafting_profits
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+/*
+The decisions made during an explain mode run. A nil trace records nothing,
+so runs that are not being explained pay nothing for it.
+*/
+type runTrace struct {
+	mutex  sync.Mutex
+	events []globalTypes.TraceEvent
+}
+
+func newRunTrace(explain bool) *runTrace {
+	if !explain {
+		return nil
+	}
+	return &runTrace{}
+}
+
+func (trace *runTrace) record(event globalTypes.TraceEvent) {
+	if trace == nil {
+		return
+	}
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	trace.events = append(trace.events, event)
+}
+
+// Every recorded event, ordered by item and recipe rather than by which analysis finished first
+func (trace *runTrace) sorted() []globalTypes.TraceEvent {
+	if trace == nil {
+		return nil
+	}
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	events := slices.Clone(trace.events)
+	slices.SortStableFunc(events, func(a, b globalTypes.TraceEvent) int {
+		return cmp.Or(
+			cmp.Compare(a.Item_id, b.Item_id),
+			cmp.Compare(a.Recipe_id, b.Recipe_id),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Detail, b.Detail),
+		)
+	})
+	return events
+}
+
+// Record which auctions of an item matched the bonus asked for and the price the run's strategy chose from them
+func (cpc *WoWCpCRunner) traceAuctionMatch(item_id globalTypes.ItemID, bonus uint, quantity float64, listed []BlizzardApi.Auction, matched []BlizzardApi.Auction, price globalTypes.AHItemPriceObject) {
+	strategy := "orderbook"
+	if cpc.Pricing != nil {
+		strategy = cpc.Pricing.Name()
+	}
+	auction_ids := make([]uint64, 0, len(matched))
+	var commodities int
+	for _, auction := range matched {
+		auction_ids = append(auction_ids, auction.Id)
+		if auction.Commodity {
+			commodities++
+		}
+	}
+	detail := fmt.Sprintf("%d of %d listings matched", len(matched), len(listed))
+	if bonus != 0 {
+		detail = fmt.Sprintf("%d of %d listings have bonus %d", len(matched), len(listed), bonus)
+	}
+	cpc.trace.record(globalTypes.TraceEvent{
+		Kind:    globalTypes.TRACE_AUCTIONS,
+		Item_id: uint(item_id),
+		Detail:  detail,
+		Values: map[string]any{
+			"bonus":               bonus,
+			"required":            quantity,
+			"auction_ids":         auction_ids,
+			"commodity_listings":  commodities,
+			"strategy":            strategy,
+			"price":               price.Price,
+			"fill_cost":           price.Fill_cost,
+			"insufficient_supply": price.Insufficient_supply,
+		},
+	})
+}
+
+// Record the recipes each of the run's professions has for an item
+func (cpc *WoWCpCRunner) traceRecipesFound(item_id globalTypes.ItemID, professions []globalTypes.CharacterProfession, status globalTypes.CraftingStatus) {
+	if cpc.trace == nil {
+		return
+	}
+	if !status.Craftable {
+		cpc.trace.record(globalTypes.TraceEvent{
+			Kind:    globalTypes.TRACE_RECIPES_FOUND,
+			Item_id: uint(item_id),
+			Detail:  "no recipe for the run's professions",
+			Values:  map[string]any{"professions": professions},
+		})
+		return
+	}
+	by_profession := make(map[string][]uint)
+	for _, recipe := range status.Recipes {
+		by_profession[recipe.Crafting_profession] = append(by_profession[recipe.Crafting_profession], recipe.Recipe_id)
+	}
+	cpc.trace.record(globalTypes.TraceEvent{
+		Kind:    globalTypes.TRACE_RECIPES_FOUND,
+		Item_id: uint(item_id),
+		Detail:  fmt.Sprintf("%d recipes from %d professions", len(status.Recipes), len(by_profession)),
+		Values:  map[string]any{"recipes": by_profession},
+	})
+}
@@ -0,0 +1,43 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestOfflineRunExplain(t *testing.T) {
+	explained := func() []globalTypes.TraceEvent {
+		config := offlineTestConfig(1001, 1)
+		config.Explain = true
+		results, err := offlineTestRunner(t).RunWithJSONConfig(context.Background(), config)
+		if err != nil {
+			t.Fatalf("RunWithJSONConfig() error = %v", err)
+		}
+		return results.Trace
+	}
+
+	trace := explained()
+	for _, kind := range []globalTypes.TraceKind{globalTypes.TRACE_AUCTIONS, globalTypes.TRACE_VENDOR_PRICE, globalTypes.TRACE_RECIPES_FOUND, globalTypes.TRACE_RANK} {
+		if !slices.ContainsFunc(trace, func(event globalTypes.TraceEvent) bool { return event.Kind == kind }) {
+			t.Errorf("trace has no %s event", kind)
+		}
+	}
+
+	first, _ := json.Marshal(trace)
+	second, _ := json.Marshal(explained())
+	if string(first) != string(second) {
+		t.Error("explaining the same run twice produced different traces")
+	}
+
+	results, err := offlineTestRunner(t).RunWithJSONConfig(context.Background(), offlineTestConfig(1001, 1))
+	if err != nil {
+		t.Fatalf("RunWithJSONConfig() error = %v", err)
+	}
+	if results.Trace != nil {
+		t.Errorf("RunWithJSONConfig() without explain traced %d events", len(results.Trace))
+	}
+}
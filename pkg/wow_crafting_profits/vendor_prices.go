@@ -0,0 +1,92 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+/*
+Retrieve the vendor price of a single unit of an item and where that price came from.
+The run's overrides win over the static vendor list. Items on neither are not sold by vendors,
+unless the run asks to guess from the item's description, which only works for English descriptions.
+Items that cannot be bought from vendors are given a value of -1.
+*/
+func (cpc *WoWCpCRunner) findNoneAHPrice(ctx context.Context, item_id globalTypes.ItemID, region globalTypes.RegionCode) (float64, globalTypes.VendorPriceSource, error) {
+	if price, found := cpc.vendorPrices[item_id]; found {
+		return cpc.tracedVendorPrice(item_id, listedVendorPrice(price), globalTypes.VENDOR_PRICE_OVERRIDE, "price set by the run", nil), globalTypes.VENDOR_PRICE_OVERRIDE, nil
+	}
+	if listed, found := (*cpc.staticSources.GetVendorPrices())[fmt.Sprint(item_id)]; found {
+		return cpc.tracedVendorPrice(item_id, listedVendorPrice(listed.Price), globalTypes.VENDOR_PRICE_STATIC, "price from the static vendor list", nil), globalTypes.VENDOR_PRICE_STATIC, nil
+	}
+
+	if !cpc.guessVendorPrices {
+		return cpc.tracedVendorPrice(item_id, -1, "", "not on either vendor list, not sold by vendors", nil), "", nil
+	}
+
+	// Get the item from blizz and guess from its description
+	item, err := cpc.Helper.GetItemDetails(ctx, item_id, region)
+	if err != nil {
+		return 0, "", err
+	}
+	vendor_price, heuristic := descriptionVendorPrice(item)
+	cpc.Logger.Debugf("%s (%d) is not on the static vendor list, guessing its vendor price from the description: %s", item.Name, item_id, heuristic)
+	values := map[string]any{
+		"description":       item.Description,
+		"purchase_price":    item.Purchase_price,
+		"purchase_quantity": item.Purchase_quantity,
+	}
+	return cpc.tracedVendorPrice(item_id, vendor_price, globalTypes.VENDOR_PRICE_DESCRIPTION, heuristic, values), globalTypes.VENDOR_PRICE_DESCRIPTION, nil
+}
+
+// Listed prices of 0 or less mark items vendors do not sell
+func listedVendorPrice(price float64) float64 {
+	if price <= 0 {
+		return -1
+	}
+	return price
+}
+
+// Guess whether vendors sell an item from its description, returning the unit price and which rule decided it
+func descriptionVendorPrice(item BlizzardApi.Item) (float64, string) {
+	vendor_price := float64(-1)
+	heuristic := "description mentions the auction house, not sold by vendors"
+	if item.Description != "" {
+		if strings.Contains(item.Description, "vendor") {
+			vendor_price = float64(item.Purchase_price)
+			heuristic = "description mentions a vendor"
+		}
+
+		if !(strings.Contains(item.Description, "auction")) {
+			vendor_price = float64(item.Purchase_price)
+			heuristic = "description does not mention the auction house"
+		}
+	} else {
+		vendor_price = float64(item.Purchase_price)
+		heuristic = "no description, using the purchase price"
+	}
+	if item.Purchase_quantity != 0 {
+		vendor_price = vendor_price / float64(item.Purchase_quantity)
+	}
+	return vendor_price, heuristic
+}
+
+func (cpc *WoWCpCRunner) tracedVendorPrice(item_id globalTypes.ItemID, price float64, source globalTypes.VendorPriceSource, detail string, values map[string]any) float64 {
+	if cpc.trace != nil {
+		if values == nil {
+			values = make(map[string]any)
+		}
+		values["source"] = source
+		values["vendor_price"] = price
+		cpc.trace.record(globalTypes.TraceEvent{
+			Kind:    globalTypes.TRACE_VENDOR_PRICE,
+			Item_id: uint(item_id),
+			Detail:  detail,
+			Values:  values,
+		})
+	}
+	return price
+}
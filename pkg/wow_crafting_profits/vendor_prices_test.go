@@ -0,0 +1,83 @@
+package wow_crafting_profits
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+func TestFindNoneAHPrice(t *testing.T) {
+	dir := t.TempDir()
+	vendor_data := `{
+		"2001": {"Name": "Test Herb", "Price": 0},
+		"3001": {"Name": "Listed Thread", "Price": 25}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "vendor-prices.json"), []byte(vendor_data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cpc := offlineTestRunner(t)
+	cpc.staticSources.RootDirectory = dir
+	cpc.vendorPrices = map[globalTypes.ItemID]float64{3001: 40}
+
+	tests := []struct {
+		name       string
+		item_id    globalTypes.ItemID
+		overrides  map[globalTypes.ItemID]float64
+		guess      bool
+		wantPrice  float64
+		wantSource globalTypes.VendorPriceSource
+	}{
+		{name: "run override", item_id: 3001, wantPrice: 40, wantSource: globalTypes.VENDOR_PRICE_OVERRIDE},
+		{name: "static list", item_id: 3001, overrides: map[globalTypes.ItemID]float64{}, wantPrice: 25, wantSource: globalTypes.VENDOR_PRICE_STATIC},
+		{name: "static list marks item not sold", item_id: 2001, wantPrice: -1, wantSource: globalTypes.VENDOR_PRICE_STATIC},
+		{name: "not on either list", item_id: 2002, wantPrice: -1, wantSource: ""},
+		{name: "description guess", item_id: 2002, guess: true, wantPrice: 50, wantSource: globalTypes.VENDOR_PRICE_DESCRIPTION},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := *cpc
+			if tt.overrides != nil {
+				runner.vendorPrices = tt.overrides
+			}
+			runner.guessVendorPrices = tt.guess
+			price, source, err := runner.findNoneAHPrice(context.Background(), tt.item_id, "us")
+			if err != nil {
+				t.Fatalf("findNoneAHPrice() error = %v", err)
+			}
+			if price != tt.wantPrice || source != tt.wantSource {
+				t.Errorf("findNoneAHPrice() = %v from %s, want %v from %s", price, source, tt.wantPrice, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestShippedVendorPrices(t *testing.T) {
+	cpc := offlineTestRunner(t)
+
+	tests := []struct {
+		name      string
+		item_id   globalTypes.ItemID
+		wantPrice float64
+	}{
+		{name: "Coarse Thread", item_id: 2320, wantPrice: 10},
+		{name: "Weak Flux", item_id: 2880, wantPrice: 100},
+		{name: "Eternium Thread", item_id: 38426, wantPrice: 30000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, source, err := cpc.findNoneAHPrice(context.Background(), tt.item_id, "us")
+			if err != nil {
+				t.Fatalf("findNoneAHPrice() error = %v", err)
+			}
+			if price != tt.wantPrice || source != globalTypes.VENDOR_PRICE_STATIC {
+				t.Errorf("findNoneAHPrice() = %v from %s, want %v from %s", price, source, tt.wantPrice, globalTypes.VENDOR_PRICE_STATIC)
+			}
+		})
+	}
+}
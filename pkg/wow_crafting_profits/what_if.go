@@ -0,0 +1,189 @@
+package wow_crafting_profits
+
+import (
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+)
+
+// Stop counting profitable crafts here, far beyond what anyone would craft in one session
+const what_if_max_crafts uint = 10000
+
+/*
+Pick the sale price the what-if is run at for a recipe rank, a fixed target price wins over undercutting.
+Undercuts start from the rank's lowest listing, or the item's when the rank has none.
+Returns false when the run did not ask for a what-if or there is nothing to undercut.
+*/
+func whatIfSalePrice(price_data globalTypes.ProfitAnalysisObject, option globalTypes.RecipeOption, json_config *globalTypes.RunConfiguration) (float64, bool) {
+	if json_config.Target_price > 0 {
+		return json_config.Target_price, true
+	}
+	if json_config.Undercut_percent <= 0 {
+		return 0, false
+	}
+	var low float64
+	if option.Rank_ah.Total_sales > 0 {
+		low = option.Rank_ah.Low
+	} else if price_data.Ah_price.Total_sales > 0 {
+		low = price_data.Ah_price.Low
+	} else {
+		return 0, false
+	}
+	return low * (1 - json_config.Undercut_percent/100), true
+}
+
+/*
+Work out what selling the crafted item at the configured price means for every recipe rank:
+the most each reagent could cost before the craft stops breaking even, and how many crafts
+stay profitable while walking up the auction house listings of the reagents that are bought there.
+Reagents are valued using the make vs. buy decisions, so analyzeMakeVsBuy must run first.
+*/
+func (cpc *WoWCpCRunner) analyzeWhatIf(price_data globalTypes.ProfitAnalysisObject, json_config *globalTypes.RunConfiguration) []globalTypes.WhatIfReport {
+	var reports []globalTypes.WhatIfReport
+
+	deposit := estimateDeposit(price_data.Sell_price, json_config.Listing_duration)
+	for _, option := range price_data.Recipe_options {
+		sale_price, ok := whatIfSalePrice(price_data, option, json_config)
+		if !ok {
+			continue
+		}
+		if report, ok := cpc.whatIfRecipe(option, sale_price, sale_price-sale_price*ah_cut_rate-deposit); ok {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
+
+/*
+Build the what-if for one recipe rank, margin is what a single sale keeps after the auction house cut and deposit.
+Returns false when a reagent of the recipe cannot be acquired.
+*/
+func (cpc *WoWCpCRunner) whatIfRecipe(option globalTypes.RecipeOption, sale_price float64, margin float64) (globalTypes.WhatIfReport, bool) {
+	report := globalTypes.WhatIfReport{
+		Recipe_id:  option.Recipe.Recipe_id,
+		Rank:       option.Rank,
+		Sale_price: sale_price,
+		Reagents:   make([]globalTypes.WhatIfReagent, 0, len(option.Prices)),
+	}
+
+	per_craft := float64(0)
+	for _, part := range option.Prices {
+		if part.Acquisition == nil || part.Acquisition.Method == globalTypes.ACQUIRE_UNAVAILABLE {
+			return globalTypes.WhatIfReport{}, false
+		}
+		per_craft += part.Acquisition.Unit_cost * part.Item_quantity
+		report.Reagents = append(report.Reagents, globalTypes.WhatIfReagent{
+			Id:        globalTypes.ItemID(part.Item_id),
+			Name:      part.Item_name,
+			Quantity:  part.Item_quantity,
+			Unit_cost: part.Acquisition.Unit_cost,
+			Method:    part.Acquisition.Method,
+		})
+	}
+
+	_, unit_cost := craftUnitCosts(per_craft, option.Crafted_quantity, option.Expected_quantity, option.Reagent_savings)
+	report.Unit_cost = unit_cost
+	report.Net_profit = margin - unit_cost
+
+	expected := whatIfExpectedOutput(option)
+	craft_revenue := margin * expected
+
+	// The most a craft's reagents can cost before resourcefulness, and still break even
+	spendable := float64(0)
+	if option.Reagent_savings < 1 {
+		spendable = craft_revenue / (1 - option.Reagent_savings)
+	}
+	for i := range report.Reagents {
+		reagent := &report.Reagents[i]
+		if reagent.Quantity <= 0 {
+			continue
+		}
+		others := per_craft - reagent.Unit_cost*reagent.Quantity
+		reagent.Break_even = max(0, (spendable-others)/reagent.Quantity)
+	}
+
+	report.Max_crafts, report.Unlimited = cpc.profitableCrafts(option, craft_revenue)
+	report.Max_quantity = float64(report.Max_crafts) * expected
+
+	return report, true
+}
+
+// Average items made by a craft, falling back to the recipe's listed output
+func whatIfExpectedOutput(option globalTypes.RecipeOption) float64 {
+	if option.Expected_quantity > 0 {
+		return option.Expected_quantity
+	}
+	if option.Crafted_quantity > 0 {
+		return option.Crafted_quantity
+	}
+	return 1
+}
+
+/*
+Count the crafts that each still earn at least their cost, buying auction house reagents from the cheapest listing up.
+Reagents that are crafted, converted or bought from vendors cost the same for every craft.
+When nothing is bought on the auction house supply never runs out, so a profitable recipe is unlimited.
+*/
+func (cpc *WoWCpCRunner) profitableCrafts(option globalTypes.RecipeOption, craft_revenue float64) (uint, bool) {
+	fixed_cost := float64(0)
+	var books []*listingCursor
+	for _, part := range option.Prices {
+		used := part.Item_quantity * (1 - option.Reagent_savings)
+		if part.Acquisition.Method == globalTypes.ACQUIRE_BUY_AH {
+			books = append(books, &listingCursor{
+				listings: auctionPriceListings(cpc.itemAuctions(globalTypes.ItemID(part.Item_id))),
+				per_take: used,
+			})
+			continue
+		}
+		fixed_cost += part.Acquisition.Unit_cost * used
+	}
+
+	if len(books) == 0 {
+		return 0, fixed_cost <= craft_revenue
+	}
+
+	var crafts uint
+	for crafts < what_if_max_crafts {
+		cost := fixed_cost
+		filled := true
+		for _, book := range books {
+			book_cost, ok := book.take()
+			cost += book_cost
+			filled = filled && ok
+		}
+		if !filled || cost > craft_revenue {
+			break
+		}
+		crafts++
+	}
+	return crafts, false
+}
+
+// Buys a fixed quantity at a time from an order book, cheapest listings first
+type listingCursor struct {
+	listings []PriceListing
+	per_take float64
+	level    int
+	used     float64 // Units already bought from the current level
+}
+
+// Buy the next per_take units, returns false when the listings run out first
+func (c *listingCursor) take() (float64, bool) {
+	cost := float64(0)
+	needed := c.per_take
+	for needed > 0 {
+		if c.level >= len(c.listings) {
+			return cost, false
+		}
+		listing := c.listings[c.level]
+		bought := min(float64(listing.Quantity)-c.used, needed)
+		cost += bought * listing.Price
+		needed -= bought
+		c.used += bought
+		if c.used >= float64(listing.Quantity) {
+			c.level++
+			c.used = 0
+		}
+	}
+	return cost, true
+}
@@ -0,0 +1,122 @@
+package wow_crafting_profits
+
+import (
+	"testing"
+
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
+)
+
+// An item crafted from 2 of item 2, bought on the auction house unless a vendor is cheaper, and 1 of item 3 at 10 from a vendor
+func whatIfTestTree(herb_vendor float64) globalTypes.ProfitAnalysisObject {
+	option := globalTypes.RecipeOption{
+		Rank:             1,
+		Crafted_quantity: 1,
+		Prices: []globalTypes.ProfitAnalysisObject{
+			{Item_id: 2, Item_name: "Herb", Item_quantity: 2, Vendor_price: herb_vendor, Ah_price: globalTypes.AHItemPriceObject{Total_sales: 14, Low: 20, Price: 20}},
+			{Item_id: 3, Item_name: "Vial", Item_quantity: 1, Vendor_price: 10},
+		},
+	}
+	option.Recipe.Recipe_id = 77
+	tree := globalTypes.ProfitAnalysisObject{
+		Item_id:        1,
+		Item_quantity:  1,
+		Ah_price:       globalTypes.AHItemPriceObject{Total_sales: 1, Low: 200, Price: 200},
+		Recipe_options: []globalTypes.RecipeOption{option},
+	}
+	decideAcquisition(&tree, nil)
+	return tree
+}
+
+func TestAnalyzeWhatIf(t *testing.T) {
+	cpc := &WoWCpCRunner{
+		indexedAuctions: map[globalTypes.ItemID][]BlizzardApi.Auction{
+			2: {
+				{Quantity: 4, Unit_price: 20},
+				{Quantity: 10, Unit_price: 40},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tree          globalTypes.ProfitAnalysisObject
+		config        globalTypes.RunConfiguration
+		wantReports   int
+		wantSale      float64
+		wantNet       float64
+		wantBreakEven []float64
+		wantCrafts    uint
+		wantUnlimited bool
+	}{
+		{
+			name:        "no what-if requested",
+			tree:        whatIfTestTree(0),
+			wantReports: 0,
+		},
+		{
+			name:          "target price stops at the end of supply",
+			tree:          whatIfTestTree(0),
+			config:        globalTypes.RunConfiguration{Target_price: 100},
+			wantReports:   1,
+			wantSale:      100,
+			wantNet:       45,
+			wantBreakEven: []float64{42.5, 55},
+			wantCrafts:    7,
+		},
+		{
+			name:          "target price stops when the next craft loses money",
+			tree:          whatIfTestTree(0),
+			config:        globalTypes.RunConfiguration{Target_price: 80},
+			wantReports:   1,
+			wantSale:      80,
+			wantNet:       26,
+			wantBreakEven: []float64{33, 36},
+			wantCrafts:    2,
+		},
+		{
+			name:          "undercut the lowest listing",
+			tree:          whatIfTestTree(0),
+			config:        globalTypes.RunConfiguration{Undercut_percent: 50},
+			wantReports:   1,
+			wantSale:      100,
+			wantNet:       45,
+			wantBreakEven: []float64{42.5, 55},
+			wantCrafts:    7,
+		},
+		{
+			name:          "vendor reagents have no supply limit",
+			tree:          whatIfTestTree(5),
+			config:        globalTypes.RunConfiguration{Target_price: 100},
+			wantReports:   1,
+			wantSale:      100,
+			wantNet:       75,
+			wantBreakEven: []float64{42.5, 85},
+			wantUnlimited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cpc.analyzeWhatIf(tt.tree, &tt.config)
+			if len(got) != tt.wantReports {
+				t.Fatalf("analyzeWhatIf() returned %d reports, want %d", len(got), tt.wantReports)
+			}
+			if tt.wantReports == 0 {
+				return
+			}
+			report := got[0]
+			if report.Sale_price != tt.wantSale || report.Net_profit != tt.wantNet {
+				t.Errorf("analyzeWhatIf() sale %v net %v, want %v %v", report.Sale_price, report.Net_profit, tt.wantSale, tt.wantNet)
+			}
+			for i, want := range tt.wantBreakEven {
+				if report.Reagents[i].Break_even != want {
+					t.Errorf("analyzeWhatIf() %s breaks even at %v, want %v", report.Reagents[i].Name, report.Reagents[i].Break_even, want)
+				}
+			}
+			if report.Max_crafts != tt.wantCrafts || report.Unlimited != tt.wantUnlimited {
+				t.Errorf("analyzeWhatIf() max crafts %d unlimited %v, want %d %v", report.Max_crafts, report.Unlimited, tt.wantCrafts, tt.wantUnlimited)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package wow_crafting_profits
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -18,6 +19,7 @@ import (
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/cpclog"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/static_sources"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/internal/util"
+	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/auction_history"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/blizzard_api_helpers"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes"
 	"github.com/hschimke/WorldOfWarcraft_CraftingProfitCalculator-go/pkg/globalTypes/BlizzardApi"
@@ -26,13 +28,68 @@ import (
 
 type recipeCost struct {
 	High, Low, Average, Median float64
+	Price                      float64 // Cost at the run's pricing strategy
 }
 
 type WoWCpCRunner struct {
 	Helper          *blizzard_api_helpers.BlizzardApiHelper
 	staticSources   static_sources.StaticSources
 	Logger          *cpclog.CpCLog
+	Pricing         PricingStrategy                       // Default reagent pricing, walks the order book when nil
+	History         *auction_history.AuctionHistoryServer // Optional, needed for historical pricing
+	Auctions        *BlizzardApi.Auctions                 // Optional auction house snapshot used instead of live auctions
+	Progress        func(globalTypes.RunProgress)         // Optional, called as each item of a run is analyzed
 	indexedAuctions map[globalTypes.ItemID][]BlizzardApi.Auction
+
+	commodityAuctions map[globalTypes.ItemID][]BlizzardApi.Auction // Region wide commodities, shared by every realm the runner indexes
+
+	reagentQuality       uint          // Reagent quality tier to craft with, 0 for the cheapest
+	recipeReagentQuality map[uint]uint // Per recipe overrides of reagentQuality
+	crafterStats         map[globalTypes.CharacterProfession]globalTypes.CrafterStats
+	slotReagents         map[uint]globalTypes.ItemID    // Reagent chosen for each modified crafting slot type
+	vendorPrices         map[globalTypes.ItemID]float64 // Run overrides of vendor prices
+	guessVendorPrices    bool                           // Guess vendor prices from descriptions for items on neither vendor list
+
+	maxDepth      uint                         // Levels of reagents to craft, 0 for no limit
+	rawMaterials  util.Set[globalTypes.ItemID] // Reagents that are always bought
+	minCraftValue float64                      // Reagents cheaper than this are bought
+
+	memo     *analysisMemo // Sub-analyses already computed by this run, nil disables reuse
+	progress *progressTracker
+	trace    *runTrace // Decisions recorded for explain mode, nil when the run is not explained
+}
+
+/*
+Create a runner for a single run, sharing this runner's helpers but with its own auction index
+and the pricing, reagent and crafter choices of the run configuration.
+The returned context counts the run's API calls when progress is being reported.
+*/
+func (cpc *WoWCpCRunner) forRun(ctx context.Context, json_config *globalTypes.RunConfiguration) (*WoWCpCRunner, context.Context, error) {
+	pricing, err := cpc.pricingFor(json_config)
+	if err != nil {
+		return nil, ctx, err
+	}
+	if history, is_history := pricing.(HistoricalAveragePricing); is_history {
+		// Each run looks historical averages up once, the runner's default strategy is shared by every run
+		pricing = history.forRun()
+	}
+	runner := *cpc
+	runner.indexedAuctions = nil
+	runner.commodityAuctions = nil
+	runner.Pricing = pricing
+	runner.reagentQuality = json_config.Reagent_quality
+	runner.recipeReagentQuality = json_config.Recipe_reagent_quality
+	runner.crafterStats = json_config.Crafter_stats
+	runner.slotReagents = json_config.Slot_reagents
+	runner.vendorPrices = json_config.Vendor_prices
+	runner.guessVendorPrices = json_config.Guess_vendor_prices
+	runner.maxDepth = json_config.Max_depth
+	runner.rawMaterials = util.SetFromSlice(json_config.Raw_materials)
+	runner.minCraftValue = json_config.Min_craft_value
+	runner.memo = newAnalysisMemo()
+	runner.progress = newProgressTracker(cpc.Progress)
+	runner.trace = newRunTrace(json_config.Explain)
+	return &runner, runner.progress.track(ctx), nil
 }
 
 func (cpc *WoWCpCRunner) indexAuctions(auction_house *BlizzardApi.Auctions) {
@@ -42,12 +99,41 @@ func (cpc *WoWCpCRunner) indexAuctions(auction_house *BlizzardApi.Auctions) {
 	}
 }
 
+// Fetch and index the region's commodity listings, unless this runner already has them
+func (cpc *WoWCpCRunner) loadCommodities(ctx context.Context, region globalTypes.RegionCode) {
+	if cpc.commodityAuctions != nil {
+		return
+	}
+	commodities, err := cpc.Helper.GetCommodityAuctions(ctx, region)
+	if err != nil {
+		// Realm listings can still be priced without commodities
+		cpc.Logger.Errorf("Failed to fetch commodities: %v", err)
+		return
+	}
+	cpc.commodityAuctions = make(map[globalTypes.ItemID][]BlizzardApi.Auction)
+	for _, auction := range commodities.Auctions {
+		cpc.commodityAuctions[auction.Item.Id] = append(cpc.commodityAuctions[auction.Item.Id], auction)
+	}
+}
+
+// Every listing of an item, realm listings first followed by commodities
+func (cpc *WoWCpCRunner) itemAuctions(item_id globalTypes.ItemID) []BlizzardApi.Auction {
+	realm, commodities := cpc.indexedAuctions[item_id], cpc.commodityAuctions[item_id]
+	if len(commodities) == 0 {
+		return realm
+	}
+	if len(realm) == 0 {
+		return commodities
+	}
+	return append(slices.Clip(realm), commodities...)
+}
+
 /*
 Find the value of an item on the auction house.
 Items might be for sale on the auction house and be available from vendors.
-The auction house items have complicated bonus types.
+The auction house items have complicated bonus types, so callers filter the auctions first.
 */
-func getAHItemPrice(item_id globalTypes.ItemID, indexedAuctions map[globalTypes.ItemID][]BlizzardApi.Auction, bonus_level_required uint) globalTypes.AHItemPriceObject {
+func getAHItemPrice(auctions []BlizzardApi.Auction) globalTypes.AHItemPriceObject {
 	// Find the item and return best, worst, average prices
 	auction_high := float64(0)
 	auction_low := float64(math.MaxUint)
@@ -57,48 +143,27 @@ func getAHItemPrice(item_id globalTypes.ItemID, indexedAuctions map[globalTypes.
 	auctionMedian := float64(0)
 
 	var medianErr error
+	commodity := false
 
 	prices := make(map[float64]uint64)
 
-	auctions, present := indexedAuctions[item_id]
-	if !present {
-		return globalTypes.AHItemPriceObject{
-			Low: auction_low,
-		}
-	}
-
 	for _, auction := range auctions {
-		// Filter by bonus level if required.
-		// In modern WoW, we might also want to filter by quality or modifiers,
-		// but for now we'll stick to the bonus_level_required logic which covers many cases.
-		if ((bonus_level_required != 0) && (len(auction.Item.Bonus_lists) > 0 && slices.Contains(auction.Item.Bonus_lists, bonus_level_required))) || (bonus_level_required == 0) {
-			var foundPrice float64
-			// In modern API:
-			// Regular auctions have 'buyout' or 'bid'.
-			// Commodities have 'unit_price'.
-			if auction.Unit_price != 0 {
-				foundPrice = float64(auction.Unit_price)
-			} else if auction.Buyout != 0 {
-				foundPrice = float64(auction.Buyout)
-			} else if auction.Bid != 0 {
-				foundPrice = float64(auction.Bid)
-			}
-
-			if foundPrice == 0 {
-				continue
-			}
-
-			if foundPrice > auction_high {
-				auction_high = foundPrice
-			}
-			if foundPrice < auction_low {
-				auction_low = foundPrice
-			}
-			auction_average_accumulator += foundPrice * float64(auction.Quantity)
+		foundPrice := auctionUnitPrice(auction)
+		if foundPrice == 0 {
+			continue
+		}
 
-			prices[foundPrice] += uint64(auction.Quantity)
-			auction_counter += auction.Quantity
+		if foundPrice > auction_high {
+			auction_high = foundPrice
+		}
+		if foundPrice < auction_low {
+			auction_low = foundPrice
 		}
+		auction_average_accumulator += foundPrice * float64(auction.Quantity)
+
+		prices[foundPrice] += uint64(auction.Quantity)
+		auction_counter += auction.Quantity
+		commodity = commodity || auction.Commodity
 	}
 
 	if auction_counter > 0 {
@@ -116,37 +181,52 @@ func getAHItemPrice(item_id globalTypes.ItemID, indexedAuctions map[globalTypes.
 		Average:     auction_average,
 		Median:      auctionMedian,
 		Total_sales: auction_counter,
+		Commodity:   commodity,
 	}
 }
 
-/*
-Retrieve the value of the item from the vendor price,
-items that cannot be bought from
-vendors are given a value of -1.
-*/
-func (cpc *WoWCpCRunner) findNoneAHPrice(ctx context.Context, item_id globalTypes.ItemID, region globalTypes.RegionCode) (float64, error) {
-	// Get the item from blizz and see what the purchase price is
-	item, err := cpc.Helper.GetItemDetails(ctx, item_id, region)
-	if err != nil {
-		return 0, err
+func auctionMatchesBonus(auction BlizzardApi.Auction, bonus_level_required uint) bool {
+	return bonus_level_required == 0 || slices.Contains(auction.Item.Bonus_lists, bonus_level_required)
+}
+
+func auctionUnitPrice(auction BlizzardApi.Auction) float64 {
+	// In modern API:
+	// Regular auctions have 'buyout' or 'bid'.
+	// Commodities have 'unit_price'.
+	if auction.Unit_price != 0 {
+		return float64(auction.Unit_price)
+	} else if auction.Buyout != 0 {
+		return float64(auction.Buyout)
+	} else if auction.Bid != 0 {
+		return float64(auction.Bid)
 	}
+	return 0
+}
 
-	vendor_price := float64(-1)
-	if item.Description != "" {
-		if strings.Contains(item.Description, "vendor") {
-			vendor_price = float64(item.Purchase_price)
+// Keep only the auctions that match
+func filterAuctions(auctions []BlizzardApi.Auction, match func(BlizzardApi.Auction) bool) []BlizzardApi.Auction {
+	var filtered []BlizzardApi.Auction
+	for _, auction := range auctions {
+		if match(auction) {
+			filtered = append(filtered, auction)
 		}
+	}
+	return filtered
+}
 
-		if !(strings.Contains(item.Description, "auction")) {
-			vendor_price = float64(item.Purchase_price)
+// Group auctions into price levels, cheapest first
+func auctionPriceListings(auctions []BlizzardApi.Auction) []PriceListing {
+	quantities := make(map[float64]uint)
+	for _, auction := range auctions {
+		if price := auctionUnitPrice(auction); price != 0 {
+			quantities[price] += auction.Quantity
 		}
-	} else {
-		vendor_price = float64(item.Purchase_price)
 	}
-	if item.Purchase_quantity != 0 {
-		vendor_price = vendor_price / float64(item.Purchase_quantity)
+	listings := make([]PriceListing, 0, len(quantities))
+	for _, price := range slices.Sorted(maps.Keys(quantities)) {
+		listings = append(listings, PriceListing{Price: price, Quantity: quantities[price]})
 	}
-	return vendor_price, nil
+	return listings
 }
 
 /*
@@ -184,7 +264,12 @@ func (cpc *WoWCpCRunner) getLvlModifierForBonus(bonus_id uint) int {
 /**
  * Analyze the profit potential for constructing or buying an item based on available recipes.
  */
-func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globalTypes.RegionCode, server globalTypes.RealmName, character_professions []globalTypes.CharacterProfession, item globalTypes.ItemSoftIdentity, qauntity uint, passed_ah *BlizzardApi.Auctions, passedCyclicLinks *globalTypes.SkillTierCyclicLinks) (globalTypes.ProfitAnalysisObject, error) {
+func (cpc *WoWCpCRunner) analyzeItem(ctx context.Context, region globalTypes.RegionCode, server globalTypes.RealmName, character_professions []globalTypes.CharacterProfession, item globalTypes.ItemSoftIdentity, qauntity uint, required float64, depth uint, passed_ah *BlizzardApi.Auctions, passedCyclicLinks *globalTypes.SkillTierCyclicLinks) (globalTypes.ProfitAnalysisObject, error) {
+	// Stop early when the run has been cancelled rather than starting another subtree
+	if err := ctx.Err(); err != nil {
+		return globalTypes.ProfitAnalysisObject{}, err
+	}
+
 	// Check if we have to figure out the item id ourselves
 	var item_id uint
 	if item.ItemId != 0 {
@@ -208,10 +293,13 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 	rankings_ptr := cpc.staticSources.GetRankMappings()
 	rankings := *rankings_ptr
 
+	shopping_recipe_exclusions := cpc.staticSources.GetShoppingRecipeExclusionList()
+
 	item_detail, err := cpc.Helper.GetItemDetails(ctx, globalTypes.ItemID(item_id), region)
 	if err != nil {
 		return globalTypes.ProfitAnalysisObject{}, err
 	}
+	cpc.progress.itemAnalyzed(item_detail.Name, depth)
 
 	base_ilvl := item_detail.Level
 
@@ -229,6 +317,7 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 		Item_id:       item_id,
 		Item_name:     item_detail.Name,
 		Item_quantity: float64(qauntity),
+		Sell_price:    float64(item_detail.Sell_price),
 	}
 
 	cpc.Logger.Infof("Analyzing profits potential for %s ( %d )", item_detail.Name, item_id)
@@ -243,11 +332,12 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 
 	//Get the auction house
 	if passed_ah == nil {
-		ah, err := cpc.Helper.GetAuctionHouse(ctx, server_id, region)
+		ah, err := cpc.Helper.GetRealmAuctions(ctx, server_id, region)
 		if err != nil {
 			return globalTypes.ProfitAnalysisObject{}, err
 		}
 		auction_house = &ah
+		cpc.loadCommodities(ctx, region)
 	} else {
 		auction_house = passed_ah
 	}
@@ -257,21 +347,40 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 	}
 
 	// Get Item AH price
-	price_obj.Ah_price = getAHItemPrice(globalTypes.ItemID(item_id), cpc.indexedAuctions, 0)
+	price_obj.Ah_price = cpc.getAHPrice(ctx, region, server_id, globalTypes.ItemID(item_id), 0, required)
+	price_obj.Quality_tier, price_obj.Quality_prices = cpc.getQualityPrices(ctx, region, server_id, globalTypes.ItemID(item_id), required)
 
 	item_craftable, err := cpc.Helper.CheckIsCrafting(ctx, globalTypes.ItemID(item_id), character_professions, region, &cpc.staticSources)
 	if err != nil {
 		return globalTypes.ProfitAnalysisObject{}, err
 	}
 
+	cpc.traceRecipesFound(globalTypes.ItemID(item_id), character_professions, item_craftable)
+
+	// Pruned reagents are bought, their recipes are never looked at
+	if item_craftable.Craftable {
+		if reason := cpc.pruneReason(globalTypes.ItemID(item_id), depth, price_obj.Ah_price); reason != "" {
+			cpc.Logger.Debugf("Not crafting %s (%d): %s", item_detail.Name, item_id, reason)
+			cpc.trace.record(globalTypes.TraceEvent{
+				Kind:    globalTypes.TRACE_PRUNED,
+				Item_id: item_id,
+				Detail:  reason,
+				Values:  map[string]any{"depth": depth},
+			})
+			price_obj.Pruned = reason
+			item_craftable.Craftable = false
+		}
+	}
+
 	// Get NON AH price
 	if !item_craftable.Craftable {
-		prc, err := cpc.findNoneAHPrice(ctx, globalTypes.ItemID(item_id), region)
+		prc, source, err := cpc.findNoneAHPrice(ctx, globalTypes.ItemID(item_id), region)
 		if err != nil {
 			return globalTypes.ProfitAnalysisObject{}, err
 		}
 
 		price_obj.Vendor_price = prc
+		price_obj.Vendor_source = source
 	} else {
 		price_obj.Vendor_price = 0
 	}
@@ -315,18 +424,37 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 					return err
 				}
 
+				crafted_quantity := getRecipeOutputValues(item_bom, &cpc.staticSources).Value
+				// Each craft makes crafted_quantity, so fewer crafts are needed to cover the requirement
+				crafts_required := required
+				if crafted_quantity > 0 {
+					crafts_required = required / crafted_quantity
+				}
+
 				// Reagent prices analysis
 				bom_prices := make([]globalTypes.ProfitAnalysisObject, len(item_bom.Reagents))
 				rg, rgCtx := errgroup.WithContext(gCtx)
 
 				for j, reagent := range item_bom.Reagents {
 					j, reagent := j, reagent
-					if _, fnd := craftable_item_swaps[reagent.Reagent.Id]; fnd {
-						return fmt.Errorf("cycles are not supported for reagent %d", reagent.Reagent.Id)
-					}
 					rg.Go(func() error {
-						itm := globalTypes.ItemSoftIdentity{ItemId: reagent.Reagent.Id}
-						new_analysis, err := cpc.performProfitAnalysis(rgCtx, region, server, character_professions, itm, reagent.Quantity, auction_house, passedCyclicLinks)
+						var new_analysis globalTypes.ProfitAnalysisObject
+						var err error
+						reagent_required := crafts_required * float64(reagent.Quantity)
+						reagent_id := cpc.chooseReagentTier(rgCtx, region, server_id, recipe.Recipe_id, reagent.Reagent.Id, reagent_required)
+						if _, fnd := craftable_item_swaps[reagent_id]; fnd {
+							// Recursing into a cyclic reagent would never terminate, price it through its cycle instead
+							cpc.trace.record(globalTypes.TraceEvent{
+								Kind:      globalTypes.TRACE_CYCLIC,
+								Item_id:   uint(reagent_id),
+								Recipe_id: recipe.Recipe_id,
+								Detail:    "reagent is part of a conversion cycle",
+							})
+							new_analysis, err = cpc.performCyclicAnalysis(rgCtx, region, server_id, reagent_id, reagent.Quantity, reagent_required, craftable_item_swaps)
+						} else {
+							itm := globalTypes.ItemSoftIdentity{ItemId: reagent_id}
+							new_analysis, err = cpc.performProfitAnalysis(rgCtx, region, server, character_professions, itm, reagent.Quantity, reagent_required, depth+1, auction_house, passedCyclicLinks)
+						}
 						if err != nil {
 							return err
 						}
@@ -338,25 +466,55 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 					return err
 				}
 
-				rank_level := uint(0)
-				var rank_AH globalTypes.AHItemPriceObject
-				if len(recipe_id_list) > 1 {
-					if loc := slices.Index(recipe_id_list, recipe.Recipe_id); loc != -1 {
-						if loc < len(rankings.Rank_mapping) {
-							rank_level = rankings.Available_levels[rankings.Rank_mapping[loc]]
-						}
+				// Reagents chosen for optional and finishing slots are priced like any other part
+				slots := cpc.recipeSlots(gCtx, region, server_id, item_bom, crafts_required)
+				for _, slot := range slots {
+					if slot.Chosen == 0 {
+						continue
 					}
-					if bonus_link[rank_level] != 0 {
-						rank_AH = getAHItemPrice(globalTypes.ItemID(item_id), cpc.indexedAuctions, bonus_link[rank_level])
+					itm := globalTypes.ItemSoftIdentity{ItemId: slot.Chosen}
+					slot_analysis, err := cpc.performProfitAnalysis(gCtx, region, server, character_professions, itm, slot.Quantity, crafts_required*float64(slot.Quantity), depth+1, auction_house, passedCyclicLinks)
+					if err != nil {
+						return err
 					}
+					bom_prices = append(bom_prices, slot_analysis)
+				}
+
+				rank_level, rank_source := recipeRankLevel(item_bom, recipe_id_list, rankings)
+				cpc.trace.record(globalTypes.TraceEvent{
+					Kind:      globalTypes.TRACE_RANK,
+					Item_id:   item_id,
+					Recipe_id: recipe.Recipe_id,
+					Detail:    rank_source,
+					Values:    map[string]any{"rank": rank_level, "bonus": bonus_link[rank_level]},
+				})
+				if slices.Contains(shopping_recipe_exclusions.Exclusions, recipe.Recipe_id) {
+					cpc.trace.record(globalTypes.TraceEvent{
+						Kind:      globalTypes.TRACE_EXCLUSION,
+						Item_id:   item_id,
+						Recipe_id: recipe.Recipe_id,
+						Detail:    "recipe is on the shopping recipe exclusion list, the item is bought instead",
+					})
 				}
+				var rank_AH globalTypes.AHItemPriceObject
+				if rank_level != 0 && bonus_link[rank_level] != 0 {
+					rank_AH = cpc.getAHPrice(gCtx, region, server_id, globalTypes.ItemID(item_id), bonus_link[rank_level], required)
+				}
+
+				stats := cpc.crafterStats[recipe.Crafting_profession]
 
 				mu.Lock()
 				recipeOptions[i] = globalTypes.RecipeOption{
-					Recipe:  recipe,
-					Prices:  bom_prices,
-					Rank:    rank_level,
-					Rank_ah: rank_AH,
+					Recipe:           recipe,
+					Prices:           bom_prices,
+					Rank:             rank_level,
+					Rank_ah:          rank_AH,
+					Crafted_quantity: crafted_quantity,
+
+					Expected_quantity: expectedCraftOutput(crafted_quantity, stats),
+					Reagent_savings:   expectedReagentSavings(stats),
+
+					Slots: slots,
 				}
 				mu.Unlock()
 				return nil
@@ -366,6 +524,7 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 		if err := g.Wait(); err != nil {
 			return globalTypes.ProfitAnalysisObject{}, err
 		}
+		sortRecipeOptions(recipeOptions)
 		price_obj.Recipe_options = recipeOptions
 
 	} else {
@@ -382,7 +541,7 @@ func (cpc *WoWCpCRunner) performProfitAnalysis(ctx context.Context, region globa
 					Ah    globalTypes.AHItemPriceObject
 				}{
 					Level: uint(int(base_ilvl) + rbl.Level),
-					Ah:    getAHItemPrice(globalTypes.ItemID(item_id), cpc.indexedAuctions, bonus),
+					Ah:    cpc.getAHPrice(ctx, region, server_id, globalTypes.ItemID(item_id), bonus, required),
 				}
 				price_obj.Bonus_prices = append(price_obj.Bonus_prices, level_uncrafted_ah_cost)
 			}
@@ -396,11 +555,23 @@ func (cpc *WoWCpCRunner) recipeCostCalculator(recipe_option globalTypes.RecipeOp
 	var cost recipeCost
 
 	for _, component := range recipe_option.Prices {
-		if component.Vendor_price > 0 {
+		if component.Unpriced {
+			// Flagged as short on supply instead of costed at an impossible price
+			continue
+		}
+		if component.Cyclic_conversion != nil {
+			conversion_cost := component.Cyclic_conversion.Unit_cost * component.Item_quantity
+			cost.High += conversion_cost
+			cost.Low += conversion_cost
+			cost.Average += conversion_cost
+			cost.Median += conversion_cost
+			cost.Price += conversion_cost
+		} else if component.Vendor_price > 0 {
 			cost.High += component.Vendor_price * component.Item_quantity
 			cost.Low += component.Vendor_price * component.Item_quantity
 			cost.Average += component.Vendor_price * component.Item_quantity
 			cost.Median += component.Vendor_price * component.Item_quantity
+			cost.Price += component.Vendor_price * component.Item_quantity
 		} else if !component.Crafting_status.Craftable {
 			high := float64(0)
 			low := float64(math.MaxUint64)
@@ -422,12 +593,14 @@ func (cpc *WoWCpCRunner) recipeCostCalculator(recipe_option globalTypes.RecipeOp
 			cost.High += high * component.Item_quantity
 			cost.Low += low * component.Item_quantity
 			cost.Median += component.Ah_price.Median * component.Item_quantity
+			cost.Price += ahUnitCost(component.Ah_price) * component.Item_quantity
 		} else {
 			ave_acc := float64(0)
 			ave_cnt := 0
 			high := float64(0)
 			low := math.MaxFloat64
 			costs := make([]float64, 0, len(component.Recipe_options))
+			cheapest := math.Inf(1)
 
 			for _, opt := range component.Recipe_options {
 				recurse_price := cpc.recipeCostCalculator(opt)
@@ -438,12 +611,16 @@ func (cpc *WoWCpCRunner) recipeCostCalculator(recipe_option globalTypes.RecipeOp
 					low = recurse_price.Low * component.Item_quantity
 				}
 				costs = append(costs, recurse_price.Median*component.Item_quantity)
+				cheapest = min(cheapest, recurse_price.Price*component.Item_quantity)
 				ave_acc += recurse_price.Average * float64(component.Item_quantity)
 				ave_cnt++
 			}
 
 			cost.Low = low
 			cost.High = high
+			if !math.IsInf(cheapest, 1) {
+				cost.Price += cheapest
+			}
 			if ave_cnt > 0 {
 				cost.Average += ave_acc / float64(ave_cnt)
 			}
@@ -462,6 +639,14 @@ func (cpc *WoWCpCRunner) recipeCostCalculator(recipe_option globalTypes.RecipeOp
 	return cost
 }
 
+// What a unit bought on the auction house costs at the run's pricing strategy, the average fill price when the strategy set none
+func ahUnitCost(price globalTypes.AHItemPriceObject) float64 {
+	if price.Price == 0 && price.Fill_cost > 0 && price.Required > 0 {
+		return price.Fill_cost / price.Required
+	}
+	return price.Price
+}
+
 func (cpc *WoWCpCRunner) generateOutputFormat(ctx context.Context, price_data globalTypes.ProfitAnalysisObject, region globalTypes.RegionCode) globalTypes.OutputFormatObject {
 	object_output := globalTypes.OutputFormatObject{
 		Name:         price_data.Item_name,
@@ -472,16 +657,31 @@ func (cpc *WoWCpCRunner) generateOutputFormat(ctx context.Context, price_data gl
 	}
 
 	if price_data.Ah_price.Total_sales > 0 {
-		object_output.Ah = globalTypes.OutputFormatPrice{
-			Sales:   price_data.Ah_price.Total_sales,
-			High:    price_data.Ah_price.High,
-			Low:     price_data.Ah_price.Low,
-			Average: price_data.Ah_price.Average,
-			Median:  price_data.Ah_price.Median,
-		}
+		object_output.Ah = outputFormatPrice(price_data.Ah_price)
 	}
 	if price_data.Vendor_price > 0 {
 		object_output.Vendor = price_data.Vendor_price
+		object_output.Vendor_source = price_data.Vendor_source
+	}
+	if price_data.Cyclic_conversion != nil {
+		object_output.Conversion = generateConversionOutputFormat(price_data.Cyclic_conversion)
+	}
+	object_output.Quality_tier = price_data.Quality_tier
+	object_output.Pruned = price_data.Pruned
+	for _, quality_price := range price_data.Quality_prices {
+		object_output.Quality_prices = append(object_output.Quality_prices, globalTypes.OutputFormatQualityPrice{
+			Tier: quality_price.Tier,
+			Id:   quality_price.Item_id,
+			Ah:   outputFormatPrice(quality_price.Ah),
+		})
+	}
+	if price_data.Acquisition != nil {
+		object_output.Optimal = &globalTypes.OutputFormatAcquisition{
+			Method:     price_data.Acquisition.Method,
+			Unit_cost:  price_data.Acquisition.Unit_cost,
+			Total_cost: price_data.Acquisition.Total_cost,
+			Recipe_id:  price_data.Acquisition.Recipe_id,
+		}
 	}
 
 	for _, recipe_option := range price_data.Recipe_options {
@@ -499,17 +699,25 @@ func (cpc *WoWCpCRunner) generateOutputFormat(ctx context.Context, price_data gl
 			Low:     option_price.Low,
 			Average: option_price.Average,
 			Median:  option_price.Median,
+			Price:   option_price.Price,
 			Parts:   make([]globalTypes.OutputFormatObject, 0, len(recipe_option.Prices)),
+
+			Optimal_cost:    recipe_option.Optimal_cost,
+			Unavailable:     recipe_option.Unavailable,
+			Reagent_savings: recipe_option.Reagent_savings,
+			Slots:           generateSlotsOutputFormat(recipe_option.Slots),
+		}
+		obj_recipe.Output.Expected = recipe_option.Expected_quantity
+		if !obj_recipe.Unavailable {
+			craft_cost := obj_recipe.Optimal_cost
+			if craft_cost == 0 {
+				craft_cost = obj_recipe.Price
+			}
+			obj_recipe.Unit_cost, obj_recipe.Expected_unit_cost = craftUnitCosts(craft_cost, obj_recipe.Output.Value, obj_recipe.Output.Expected, obj_recipe.Reagent_savings)
 		}
 
 		if recipe_option.Rank_ah.Total_sales > 0 {
-			obj_recipe.Ah = globalTypes.OutputFormatPrice{
-				Sales:   recipe_option.Rank_ah.Total_sales,
-				High:    recipe_option.Rank_ah.High,
-				Low:     recipe_option.Rank_ah.Low,
-				Average: recipe_option.Rank_ah.Average,
-				Median:  recipe_option.Rank_ah.Median,
-			}
+			obj_recipe.Ah = outputFormatPrice(recipe_option.Rank_ah)
 		}
 
 		for _, opt := range recipe_option.Prices {
@@ -522,18 +730,54 @@ func (cpc *WoWCpCRunner) generateOutputFormat(ctx context.Context, price_data gl
 	for _, bonus_price := range price_data.Bonus_prices {
 		object_output.Bonus_prices = append(object_output.Bonus_prices, globalTypes.OutputFormatBonusPrices{
 			Level: bonus_price.Level,
-			Ah: globalTypes.OutputFormatPrice{
-				Sales:   bonus_price.Ah.Total_sales,
-				High:    bonus_price.Ah.High,
-				Low:     bonus_price.Ah.Low,
-				Average: bonus_price.Ah.Average,
-				Median:  bonus_price.Ah.Median,
-			}})
+			Ah:    outputFormatPrice(bonus_price.Ah),
+		})
 	}
 
 	return object_output
 }
 
+func outputFormatPrice(price globalTypes.AHItemPriceObject) globalTypes.OutputFormatPrice {
+	return globalTypes.OutputFormatPrice{
+		Sales:   price.Total_sales,
+		High:    price.High,
+		Low:     price.Low,
+		Average: price.Average,
+		Median:  price.Median,
+		Price:   price.Price,
+
+		Required:            price.Required,
+		Fill_cost:           price.Fill_cost,
+		Insufficient_supply: price.Insufficient_supply,
+		Commodity:           price.Commodity,
+	}
+}
+
+func generateConversionOutputFormat(conversion *globalTypes.CyclicConversion) *globalTypes.OutputFormatConversion {
+	conversion_output := globalTypes.OutputFormatConversion{
+		Source_id:   conversion.Source_id,
+		Source_name: conversion.Source_name,
+		Ratio:       conversion.Ratio,
+		Unit_cost:   conversion.Unit_cost,
+		Chain:       make([]globalTypes.OutputFormatConversionStep, 0, len(conversion.Chain)),
+	}
+	if conversion.Source_ah.Total_sales > 0 {
+		conversion_output.Ah = outputFormatPrice(conversion.Source_ah)
+	}
+	if conversion.Source_vendor > 0 {
+		conversion_output.Vendor = conversion.Source_vendor
+	}
+	for _, step := range conversion.Chain {
+		conversion_output.Chain = append(conversion_output.Chain, globalTypes.OutputFormatConversionStep{
+			Id:    step.Id,
+			Name:  step.Name,
+			Takes: step.Takes,
+			Makes: step.Makes,
+		})
+	}
+	return &conversion_output
+}
+
 func getRecipeOutputValues(recipe BlizzardApi.Recipe, static_source *static_sources.StaticSources) globalTypes.OutpoutFormatRecipeOutput {
 	var min, max, value float64
 
@@ -582,85 +826,78 @@ func getShoppingListRanks(intermediate_data globalTypes.OutputFormatObject) []ui
 	return ranks
 }
 
-func (cpc *WoWCpCRunner) constructShoppingList(intermediate_data globalTypes.OutputFormatObject, on_hand *globalTypes.RunConfiguration) globalTypes.OutputFormatShoppingList {
+/*
+Build every rank's shopping list, the crafts it takes and what it uses from the inventory on hand.
+Each rank starts from the whole inventory, and owned reagents and intermediates are used before anything is crafted or bought.
+*/
+func (cpc *WoWCpCRunner) constructShoppingList(intermediate_data globalTypes.OutputFormatObject, on_hand *globalTypes.RunConfiguration) (globalTypes.OutputFormatShoppingList, globalTypes.OutputFormatCraftSteps, globalTypes.OutputFormatInventoryUsed) {
 	shopping_lists := make(globalTypes.OutputFormatShoppingList)
+	craft_steps := make(globalTypes.OutputFormatCraftSteps)
+	inventory_used := make(globalTypes.OutputFormatInventoryUsed)
 	for _, rank := range getShoppingListRanks(intermediate_data) {
-		on_hand.ResetInventoryAdjustments()
-		shopping_list := cpc.build_shopping_list(intermediate_data, rank)
-		for listIndex, li := range shopping_list {
-			needed := li.Quantity
-			available := on_hand.ItemCount(li.Id)
-
-			if needed <= float64(available) {
-				needed = 0
-				on_hand.AdjustInventory(li.Id, (int(needed) * -1))
-			} else if (needed > float64(available)) && (int(available) != 0) {
-				needed -= float64(available)
-				on_hand.AdjustInventory(li.Id, (int(available) * -1))
-			}
-
-			li.Quantity = needed
+		on_hand.ResetInventoryLedger()
+		plan := cpc.buildRankPlan(intermediate_data, rank, shoppingPlan{on_hand: on_hand})
+		shopping_lists[rank] = totalShoppingListCosts(plan.shoppingList())
+		craft_steps[rank] = plan.craftSteps()
+		inventory_used[rank] = plan.inventoryUsed()
+	}
+	return shopping_lists, craft_steps, inventory_used
+}
 
-			if li.Cost.Vendor != 0 {
-				li.Cost.Vendor *= li.Quantity
-			}
-			if li.Cost.Ah.Sales != 0 {
-				li.Cost.Ah.High *= li.Quantity
-				li.Cost.Ah.Low *= li.Quantity
-				li.Cost.Ah.Median *= li.Quantity
-				li.Cost.Ah.Average *= float64(li.Quantity)
-			}
+// Plan the make vs. buy shopping list, accounting for inventory on hand
+func constructOptimalPlan(intermediate_data globalTypes.OutputFormatObject, on_hand *globalTypes.RunConfiguration) shoppingPlan {
+	on_hand.ResetInventoryLedger()
+	return buildOptimalPlan(intermediate_data, shoppingPlan{on_hand: on_hand})
+}
 
-			shopping_list[listIndex] = li
+// Scale the per unit costs of a shopping list to the quantity to buy
+func totalShoppingListCosts(shopping_list []globalTypes.ShoppingList) []globalTypes.ShoppingList {
+	for listIndex, li := range shopping_list {
+		if li.Cost.Vendor != 0 {
+			li.Cost.Vendor *= li.Quantity
+		}
+		if li.Cost.Ah.Sales != 0 {
+			li.Cost.Ah.High *= li.Quantity
+			li.Cost.Ah.Low *= li.Quantity
+			li.Cost.Ah.Median *= li.Quantity
+			li.Cost.Ah.Average *= float64(li.Quantity)
 		}
-		shopping_lists[rank] = shopping_list
+
+		shopping_list[listIndex] = li
 	}
-	return shopping_lists
+	return shopping_list
 }
 
-func (cpc *WoWCpCRunner) build_shopping_list(intermediate_data globalTypes.OutputFormatObject, rank_requested uint) []globalTypes.ShoppingList {
-	shopping_list := make([]globalTypes.ShoppingList, 0)
-
-	shopping_recipe_exclusions_ptr := cpc.staticSources.GetShoppingRecipeExclusionList()
-	shopping_recipe_exclusions := *shopping_recipe_exclusions_ptr
-
-	needed := intermediate_data.Required
+/*
+Add making the item with the recipe of the requested rank to a plan, intermediates are crafted, converted or bought
+as the make vs. buy analysis decided. Items without a recipe, or whose recipe is excluded from shopping lists, are bought.
+*/
+func (cpc *WoWCpCRunner) buildRankPlan(intermediate_data globalTypes.OutputFormatObject, rank_requested uint, plan shoppingPlan) shoppingPlan {
+	shopping_recipe_exclusions := cpc.staticSources.GetShoppingRecipeExclusionList()
 
 	if len(intermediate_data.Recipes) == 0 {
-		shopping_list = append(shopping_list, globalTypes.ShoppingList{
-			Id:       intermediate_data.Id,
-			Name:     intermediate_data.Name,
-			Quantity: intermediate_data.Required,
-			Cost: globalTypes.ShoppingListCost{
-				Ah:     intermediate_data.Ah,
-				Vendor: intermediate_data.Vendor,
-			},
-		})
-	} else {
-		for _, recipe := range intermediate_data.Recipes {
-			if slices.Contains(shopping_recipe_exclusions.Exclusions, recipe.Id) {
-				shopping_list = append(shopping_list, globalTypes.ShoppingList{
-					Id:       intermediate_data.Id,
-					Name:     intermediate_data.Name,
-					Quantity: intermediate_data.Required,
-					Cost: globalTypes.ShoppingListCost{
-						Ah:     intermediate_data.Ah,
-						Vendor: intermediate_data.Vendor,
-					},
-				})
-			} else {
-				if recipe.Rank == rank_requested {
-					for _, part := range recipe.Parts {
-						for _, sl := range cpc.build_shopping_list(part, 0) {
-							sl.Quantity = sl.Quantity * needed
-							shopping_list = append(shopping_list, sl)
-						}
-					}
-				}
+		plan.acquire(intermediate_data, intermediate_data.Required)
+		return plan
+	}
+	for _, recipe := range intermediate_data.Recipes {
+		if recipe.Rank != rank_requested {
+			continue
+		}
+		if slices.Contains(shopping_recipe_exclusions.Exclusions, recipe.Id) {
+			if needed := plan.fromInventory(intermediate_data.Id, intermediate_data.Name, intermediate_data.Required); needed > craft_rounding_tolerance {
+				plan.buy(intermediate_data, needed)
 			}
+		} else {
+			plan.craft(intermediate_data, recipe, intermediate_data.Required)
 		}
+		break
 	}
 
+	return plan
+}
+
+// Combine shopping list entries for the same item
+func mergeShoppingList(shopping_list []globalTypes.ShoppingList) []globalTypes.ShoppingList {
 	tmp := make(map[uint]globalTypes.ShoppingList)
 	for _, list_element := range shopping_list {
 		hld, present := tmp[list_element.Id]
@@ -671,10 +908,16 @@ func (cpc *WoWCpCRunner) build_shopping_list(intermediate_data globalTypes.Outpu
 			hld.Cost = list_element.Cost
 		}
 		hld.Quantity += list_element.Quantity
+		if hld.Cost.Ah.Sales > 0 && hld.Quantity > float64(hld.Cost.Ah.Sales) {
+			hld.Cost.Ah.Insufficient_supply = true
+		}
 		tmp[list_element.Id] = hld
 	}
 
-	return slices.Collect(maps.Values(tmp))
+	// Map order is random, sort so the list is the same from run to run
+	return slices.SortedFunc(maps.Values(tmp), func(a, b globalTypes.ShoppingList) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
 }
 
 func getRegionCode(region string) (region_coded globalTypes.RegionCode, err error) {
@@ -688,6 +931,22 @@ func getRegionCode(region string) (region_coded globalTypes.RegionCode, err erro
 	return
 }
 
+// Get the professions for a run, either those provided or every profession in the region
+func (cpc *WoWCpCRunner) resolveProfessions(ctx context.Context, region globalTypes.RegionCode, useAllProfessions bool, professions_input []globalTypes.CharacterProfession) ([]globalTypes.CharacterProfession, error) {
+	if !useAllProfessions {
+		return professions_input, nil
+	}
+	profList, profErr := cpc.Helper.GetBlizProfessionsList(ctx, region)
+	if profErr != nil {
+		return nil, profErr
+	}
+	professions := make([]globalTypes.CharacterProfession, 0, len(profList.Professions))
+	for _, prof := range profList.Professions {
+		professions = append(professions, globalTypes.CharacterProfession(prof.Name))
+	}
+	return professions, nil
+}
+
 func (cpc *WoWCpCRunner) run(ctx context.Context, region string, server globalTypes.RealmName, useAllProfessions bool, professions_input []globalTypes.CharacterProfession, item globalTypes.ItemSoftIdentity, json_config *globalTypes.RunConfiguration, count uint) (globalTypes.RunReturn, error) {
 
 	cpc.Logger.Info("World of Warcraft Crafting Profit Calculator")
@@ -697,38 +956,50 @@ func (cpc *WoWCpCRunner) run(ctx context.Context, region string, server globalTy
 		return globalTypes.RunReturn{Formatted: "NO DATA"}, err
 	}
 
-	var professions []globalTypes.CharacterProfession
-
-	if useAllProfessions {
-		profList, profErr := cpc.Helper.GetBlizProfessionsList(ctx, encoded_region)
-		if profErr != nil {
-			return globalTypes.RunReturn{Formatted: "NO DATA"}, profErr
-		}
-		professions = make([]globalTypes.CharacterProfession, 0, len(profList.Professions))
-		for _, prof := range profList.Professions {
-			professions = append(professions, globalTypes.CharacterProfession(prof.Name))
-		}
-	} else {
-		professions = professions_input
+	professions, err := cpc.resolveProfessions(ctx, encoded_region, useAllProfessions, professions_input)
+	if err != nil {
+		return globalTypes.RunReturn{Formatted: "NO DATA"}, err
 	}
 
-	price_data, err := cpc.performProfitAnalysis(ctx, encoded_region, server, professions, item, count, nil, nil)
+	price_data, err := cpc.performProfitAnalysis(ctx, encoded_region, server, professions, item, count, float64(count), 0, cpc.Auctions, nil)
 	if err != nil {
 		return globalTypes.RunReturn{Formatted: "NO DATA"}, err
 	}
+	cpc.analyzeMakeVsBuy(&price_data)
 	intermediate_data := cpc.generateOutputFormat(ctx, price_data, encoded_region)
-	intermediate_data.Shopping_lists = cpc.constructShoppingList(intermediate_data, json_config)
+	intermediate_data.Shopping_lists, intermediate_data.Craft_steps, intermediate_data.Inventory_used = cpc.constructShoppingList(intermediate_data, json_config)
+	optimal_plan := constructOptimalPlan(intermediate_data, json_config)
+	intermediate_data.Optimal_list = totalShoppingListCosts(optimal_plan.shoppingList())
+	intermediate_data.Optimal_steps = optimal_plan.craftSteps()
+	intermediate_data.Optimal_used = optimal_plan.inventoryUsed()
+	profits := calculateProfits(price_data, intermediate_data, json_config.Listing_duration)
 	formatted_data := text_output_helpers.TextFriendlyOutputFormat(&intermediate_data, 0)
+	formatted_data += text_output_helpers.TextFriendlyProfitFormat(intermediate_data.Name, profits)
+	what_if := cpc.analyzeWhatIf(price_data, json_config)
+	formatted_data += text_output_helpers.TextFriendlyWhatIfFormat(intermediate_data.Name, what_if)
+	budget := cpc.optimizeBudget(intermediate_data, json_config, json_config.Budget)
+	formatted_data += text_output_helpers.TextFriendlyBudgetFormat(intermediate_data.Name, budget)
 
 	return globalTypes.RunReturn{
 		Price:        price_data,
 		Intermediate: intermediate_data,
+		Profits:      profits,
+		What_if:      what_if,
+		Budget:       budget,
+		Pricing:      cpc.Pricing.Name(),
+		Pruned:       prunedNodes(price_data),
+		Stats:        cpc.memo.stats(),
+		Trace:        cpc.trace.sorted(),
 		Formatted:    formatted_data,
 	}, nil
 }
 
 func (cpc *WoWCpCRunner) RunWithJSONConfig(ctx context.Context, json_config *globalTypes.RunConfiguration) (globalTypes.RunReturn, error) {
-	return cpc.run(ctx, json_config.Realm_region, json_config.Realm_name, json_config.UseAllProfessions, json_config.Professions, json_config.Item, json_config, json_config.Item_count)
+	runner, ctx, err := cpc.forRun(ctx, json_config)
+	if err != nil {
+		return globalTypes.RunReturn{Formatted: "NO DATA"}, err
+	}
+	return runner.run(ctx, json_config.Realm_region, json_config.Realm_name, json_config.UseAllProfessions, json_config.Professions, json_config.Item, json_config, json_config.Item_count)
 }
 
 func (cpc *WoWCpCRunner) CliRun(ctx context.Context, json_config *globalTypes.RunConfiguration) error {
@@ -736,14 +1007,47 @@ func (cpc *WoWCpCRunner) CliRun(ctx context.Context, json_config *globalTypes.Ru
 	if err != nil {
 		return err
 	}
-	return saveOutput(results.Price, results.Intermediate, results.Formatted, cpc.Logger)
+	for _, profit := range results.Profits {
+		cpc.Logger.Infof("Rank %d (%d): sells for %s, costs %s, net %s (%.1f%% ROI)", profit.Rank, profit.Recipe_id, text_output_helpers.GoldFormatter(profit.Sale_price), text_output_helpers.GoldFormatter(profit.Crafting_cost), text_output_helpers.SignedGoldFormatter(profit.Net_profit), profit.Roi)
+	}
+	for _, what_if := range results.What_if {
+		cpc.Logger.Infof("Rank %d (%d) at %s: net %s, %s", what_if.Rank, what_if.Recipe_id, text_output_helpers.GoldFormatter(what_if.Sale_price), text_output_helpers.SignedGoldFormatter(what_if.Net_profit), text_output_helpers.WhatIfQuantity(what_if))
+	}
+	if results.Budget != nil {
+		best := results.Budget.Best
+		cpc.Logger.Infof("A budget of %s crafts %d with rank %d (%d) and %s intermediates for %s", text_output_helpers.GoldFormatter(results.Budget.Budget), best.Quantity, best.Rank, best.Recipe_id, best.Intermediates, text_output_helpers.GoldFormatter(best.Cost))
+	}
+	cpc.Logger.Infof("Analyzed %d items, reused %d analyses", results.Stats.Analyses, results.Stats.Memo_hits)
+	for _, pruned := range results.Pruned {
+		cpc.Logger.Infof("Bought %s (%d) at depth %d instead of crafting: %s", pruned.Item_name, pruned.Item_id, pruned.Depth, pruned.Reason)
+	}
+	return errors.Join(
+		saveOutput(results.Price, results.Intermediate, results.Formatted, results.Trace, cpc.Logger),
+		saveShoppingListExport(json_config.Export_format, results.Intermediate.Name, results.Intermediate.Optimal_list, cpc.Logger),
+	)
+}
+
+// Load an auction house snapshot saved from the Blizzard auctions api
+func LoadAuctionSnapshot(fn string) (*BlizzardApi.Auctions, error) {
+	file, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var auctions BlizzardApi.Auctions
+	if err := json.NewDecoder(file).Decode(&auctions); err != nil {
+		return nil, fmt.Errorf("error parsing auction snapshot %s: %w", fn, err)
+	}
+	return &auctions, nil
 }
 
-func saveOutput(price_data globalTypes.ProfitAnalysisObject, intermediate_data globalTypes.OutputFormatObject, formatted_data string, logger *cpclog.CpCLog) error {
+func saveOutput(price_data globalTypes.ProfitAnalysisObject, intermediate_data globalTypes.OutputFormatObject, formatted_data string, trace []globalTypes.TraceEvent, logger *cpclog.CpCLog) error {
 	const (
 		intermediate_output_fn string = "intermediate_output.json"
 		formatted_output_fn    string = "formatted_output"
 		raw_output_fn          string = "raw_output.json"
+		explain_output_fn      string = "explain_output.json"
 	)
 
 	var errs []error
@@ -794,5 +1098,61 @@ func saveOutput(price_data globalTypes.ProfitAnalysisObject, intermediate_data g
 		}
 	}
 
+	if len(trace) > 0 {
+		if err := func() error {
+			explainFile, err := os.Create(explain_output_fn)
+			if err != nil {
+				return err
+			}
+			defer explainFile.Close()
+			encoder := json.NewEncoder(explainFile)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(&trace)
+		}(); err != nil {
+			errs = append(errs, fmt.Errorf("error saving explain output: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+Save the results of a run mode as indented JSON to fn and its report to the formatted output file.
+Both files are always attempted, the errors from either are returned together.
+*/
+func saveJSONOutput(fn string, v any, formatted string, logger *cpclog.CpCLog) error {
+	const formatted_output_fn string = "formatted_output"
+
+	var errs []error
+
+	logger.Infof("Saving %s", fn)
+	if err := func() error {
+		outFile, err := os.Create(fn)
+		if err != nil {
+			return err
+		}
+		defer outFile.Close()
+		encoder := json.NewEncoder(outFile)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}(); err != nil {
+		errs = append(errs, fmt.Errorf("error saving %s: %w", fn, err))
+	}
+
+	if err := func() error {
+		forFile, err := os.Create(formatted_output_fn)
+		if err != nil {
+			return err
+		}
+		defer forFile.Close()
+		formatted_writer := bufio.NewWriter(forFile)
+		if _, err := formatted_writer.WriteString(formatted); err != nil {
+			return err
+		}
+		return formatted_writer.Flush()
+	}(); err != nil {
+		errs = append(errs, fmt.Errorf("error saving formatted output: %w", err))
+	}
+
 	return errors.Join(errs...)
 }
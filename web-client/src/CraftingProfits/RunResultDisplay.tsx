@@ -208,6 +208,7 @@ function RunResultDisplayQUEUE(props: RunResultDisplayPropsQUEUE) {
     const job_id = (raw_run as any)?.job_id;
 
     const [st, setSt] = useState(undefined as (RunReturn & ServerErrorReturn) | undefined);
+    const [progress, setProgress] = useState(undefined as RunProgress | undefined);
 
     useEffect(() => {
         let timer = setTimeout(() => {
@@ -227,6 +228,7 @@ function RunResultDisplayQUEUE(props: RunResultDisplayPropsQUEUE) {
                             setSt(data);
                         } else {
                             setSt(undefined);
+                            setProgress(data.progress);
                             timer = setTimeout(queue_checker_functino, 1000)
                         }
                     })
@@ -239,7 +241,7 @@ function RunResultDisplayQUEUE(props: RunResultDisplayPropsQUEUE) {
     }, [props.raw_run]);
 
     if (st === undefined && job_id !== undefined) {
-        return (<JobRunningBox job_id={job_id} item_name={props.item_name} />);
+        return (<JobRunningBox job_id={job_id} item_name={props.item_name} progress={progress} />);
     }
 
     let res;
@@ -274,18 +276,20 @@ function RunResultCore({ raw_run }: RunResultCoreProps) {
         const lessShopping = raw_run;
         return <div className="RunResultCore">
             <RunResultItem raw_run={lessShopping} />
-            <ShoppingLists lists={shopping} name={name} />
+            <ShoppingLists lists={shopping} steps={raw_run.craft_steps} used={raw_run.inventory_used} name={name} />
         </div>
     }
     return <></>
 }
 
-function JobRunningBox({ job_id, item_name }: { job_id: string, item_name?: string }) {
+function JobRunningBox({ job_id, item_name, progress }: { job_id: string, item_name?: string, progress?: RunProgress }) {
     return (
         <div>
             {item_name &&
                 <p>Job submitted for {item_name}</p>}
             <p>Job {job_id} is running.</p>
+            {progress &&
+                <p>Analyzed {progress.items_analyzed} items with {progress.api_calls} API calls, currently at depth {progress.depth}{progress.item && ` (${progress.item})`}.</p>}
         </div>
     );
 }
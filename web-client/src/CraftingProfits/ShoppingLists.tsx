@@ -1,20 +1,39 @@
+import { useState } from 'react';
 import './ShoppingLists.css';
 import { AHItemPrice, VendorItemPrice } from '../Shared/GoldFormatter';
+import { useFetchShoppingListExportApi } from '../Shared/ApiClient';
 
 export interface ShoppingListsProps {
     name: string,
-    lists: OutputFormatShoppingList
+    lists: OutputFormatShoppingList,
+    steps?: OutputFormatCraftSteps,
+    used?: OutputFormatInventoryUsed
 }
 
 export interface ShoppingListProps {
+    name: string,
     level: string | number,
-    list: ShoppingList[]
+    list: ShoppingList[],
+    steps?: CraftStep[],
+    used?: ReagentUse[]
 }
 
 export interface ShoppingListItemProps {
     item: ShoppingList
 }
 
+export interface ShoppingListExportProps {
+    name: string,
+    list: ShoppingList[]
+}
+
+const export_formats = [
+    { format: 'tsm_group', label: 'TSM group' },
+    { format: 'tsm_shopping', label: 'TSM shopping search' },
+    { format: 'auctionator', label: 'Auctionator shopping list' },
+    { format: 'cpc', label: 'CPC addon (/cpci)' },
+];
+
 function ShoppingLists(props: ShoppingListsProps) {
     return (
         <div className="ShoppingLists">
@@ -23,7 +42,7 @@ function ShoppingLists(props: ShoppingListsProps) {
             </span>
             <ul>
                 {Object.keys(props.lists).map(list => {
-                    return <ShoppingList key={list} list={props.lists[list]} level={list} />
+                    return <ShoppingList key={list} name={props.name} list={props.lists[list]} level={list} steps={props.steps?.[list]} used={props.used?.[list]} />
                 })}
             </ul>
         </div>
@@ -41,10 +60,65 @@ function ShoppingList(props: ShoppingListProps) {
                     return <ShoppingListItem key={JSON.stringify(list_item)} item={list_item} />
                 })}
             </ul>
+            <ShoppingListExport name={`${props.name} rank ${props.level}`} list={props.list} />
+            {(props.steps !== undefined) && (props.steps.length > 0) &&
+                <>
+                    <span className="ShoppingListTitle">
+                        Craft in order
+                    </span>
+                    <ol className="CraftSteps">
+                        {props.steps.map(step => {
+                            return <li key={`${step.id}-${step.recipe_id}`}>
+                                {step.crafts.toLocaleString()} x {step.name} ({step.id}), making {step.quantity.toLocaleString()}
+                            </li>
+                        })}
+                    </ol>
+                </>
+            }
+            {(props.used !== undefined) && (props.used.length > 0) &&
+                <>
+                    <span className="ShoppingListTitle">
+                        Used from inventory
+                    </span>
+                    <ul>
+                        {props.used.map(use => {
+                            return <li key={use.id}>
+                                {use.quantity.toLocaleString()} x {use.name} ({use.id})
+                            </li>
+                        })}
+                    </ul>
+                </>
+            }
         </li>
     );
 }
 
+function ShoppingListExport(props: ShoppingListExportProps) {
+    const [format, updateFormat] = useState(export_formats[0].format);
+    const [apiState, setPayload] = useFetchShoppingListExportApi();
+
+    const exportList = () => {
+        setPayload({ format: format, name: props.name, shopping_list: props.list });
+    };
+
+    return (
+        <div className="ShoppingListExport">
+            <select value={format} onChange={(e) => updateFormat(e.target.value)}>
+                {export_formats.map(f => {
+                    return <option key={f.format} value={f.format}>{f.label}</option>
+                })}
+            </select>
+            <button type="button" onClick={exportList}>Export</button>
+            {apiState.isError &&
+                <span>Could not export the shopping list</span>
+            }
+            {(apiState.data !== undefined) && !apiState.isLoading && !apiState.isError &&
+                <textarea readOnly value={apiState.data.export} onFocus={(e) => e.target.select()} />
+            }
+        </div>
+    );
+}
+
 function ShoppingListItem(props: ShoppingListItemProps) {
     const li = props.item;
     const show_vendor = (li.cost.vendor !== undefined) && (li.cost.vendor !== 0);
@@ -4,6 +4,10 @@ function useFetchHistoryApi() {
     return useFetchApi<AuctionHistoryReturn>('/auction_history');
 }
 
+function useFetchShoppingListExportApi() {
+    return useFetchApi<ShoppingListExportReturn>('/shopping_list_export');
+}
+
 export interface UseFetchApiState<FetchType> {
     isLoading: boolean,
     isError: boolean,
@@ -206,4 +210,4 @@ function fetchPromiseWrapper<Type>(endpoint: string, data: object | undefined =
     }
 }
 
-export { useFetchHistoryApi, useFetchApi, useFetchCPCApi, useSeenBonusesApi, fetchPromiseWrapper };
\ No newline at end of file
+export { useFetchHistoryApi, useFetchShoppingListExportApi, useFetchApi, useFetchCPCApi, useSeenBonusesApi, fetchPromiseWrapper };
\ No newline at end of file
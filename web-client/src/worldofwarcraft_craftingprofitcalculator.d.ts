@@ -235,6 +235,29 @@ interface OutputFormatPrice {
 
 type OutputFormatShoppingList = Record<number | string, ShoppingList[]>;
 
+interface ShoppingListExportReturn {
+    format: string,
+    export: string
+}
+
+interface CraftStep {
+    id: ItemID,
+    name: ItemName,
+    recipe_id: number,
+    crafts: number,
+    quantity: number
+}
+
+type OutputFormatCraftSteps = Record<number | string, CraftStep[]>;
+
+interface ReagentUse {
+    id: ItemID,
+    name: ItemName,
+    quantity: number
+}
+
+type OutputFormatInventoryUsed = Record<number | string, ReagentUse[]>;
+
 interface OutputFormatObject {
     name: string,
     id: number,
@@ -253,6 +276,8 @@ interface OutputFormatObject {
         low: number,
         average: number,
         median: number,
+        price: number,
+        unavailable?: boolean,
         parts: OutputFormatObject[]
     }[],
     ah: OutputFormatPrice,
@@ -261,7 +286,9 @@ interface OutputFormatObject {
         level: number,
         ah: OutputFormatPrice
     }[],
-    shopping_lists: OutputFormatShoppingList
+    shopping_lists: OutputFormatShoppingList,
+    craft_steps?: OutputFormatCraftSteps,
+    inventory_used?: OutputFormatInventoryUsed
 }
 
 interface RunReturn {
@@ -311,6 +338,13 @@ interface SeenItemBonusesReturn {
     }
 }
 
+interface RunProgress {
+    items_analyzed: number,
+    api_calls: number,
+    depth: number,
+    item?: string
+}
+
 interface ServerErrorReturn {
     ERROR: string
 }
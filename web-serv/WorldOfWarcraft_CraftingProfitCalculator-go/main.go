@@ -53,6 +53,7 @@ func main() {
 	// API endpoints - apply rate limiting
 	router.Handle("/json_output_QUEUED", rateLimiter.Middleware(http.HandlerFunc(cpcRoutes.JsonOutputQueue)))
 	router.Handle("/json_output_CHECK", rateLimiter.Middleware(http.HandlerFunc(cpcRoutes.JsonOutputCheck)))
+	router.Handle("/shopping_list_export", rateLimiter.Middleware(http.HandlerFunc(cpcRoutes.ShoppingListExport)))
 
 	if !environment_variables.DISABLE_AUCTION_HISTORY {
 		router.Handle("/all_items", rateLimiter.Middleware(http.HandlerFunc(cpcRoutes.AllItems)))
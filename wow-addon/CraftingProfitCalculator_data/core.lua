@@ -32,6 +32,8 @@ CraftingProfitCalculator_data.event_frame:RegisterEvent("ADDONS_UNLOADING")
 	SLASH_CPCA1 = "/cpca"
 	SLASH_CPCC1 = "/cpcc"
 	SLASH_CPCR1 = "/cpcr"
+	SLASH_CPCI1 = "/cpci"
+	SLASH_CPCL1 = "/cpcl"
 	SlashCmdList["CPCC"] = function(msg)
 		CraftingProfitCalculator_data:run_character()
 	end 
@@ -41,6 +43,12 @@ CraftingProfitCalculator_data.event_frame:RegisterEvent("ADDONS_UNLOADING")
 	SlashCmdList["CPCR"] = function(msg)
    	 CraftingProfitCalculator_data:run()
   	end
+	SlashCmdList["CPCI"] = function(msg)
+		CraftingProfitCalculator_data:import_shopping_list()
+	end
+	SlashCmdList["CPCL"] = function(msg)
+		CraftingProfitCalculator_data:show_shopping_list()
+	end
  end
 
  function CraftingProfitCalculator_data:run_character()
@@ -73,6 +81,54 @@ CraftingProfitCalculator_data.event_frame:RegisterEvent("ADDONS_UNLOADING")
 	end)
  end
  
+ -- Paste a shopping list exported from CPC in the cpc format
+ function CraftingProfitCalculator_data:import_shopping_list()
+	CraftingProfitCalculator_data:show('')
+	CPCCopyFrameButton:SetScript("OnClick", function(self)
+	  local shopping_list = CraftingProfitCalculator_data:parseShoppingList(CPCCopyFrameScrollText:GetText())
+	  if shopping_list == nil then
+		 DEFAULT_CHAT_FRAME:AddMessage('CPC: not a shopping list, export it from CPC in the cpc format')
+		 return
+	  end
+	  CraftingProfitCalculator_shoppingDB = shopping_list
+	  CraftingProfitCalculator_data:show_shopping_list()
+	end)
+ end
+
+ -- CPC1^list name^item id:quantity:item name^...
+ function CraftingProfitCalculator_data:parseShoppingList(str)
+	local fields = { strsplit("^", strtrim(str)) }
+	if fields[1] ~= 'CPC1' then
+		return nil
+	end
+	local shopping_list = {}
+	shopping_list.name = fields[2] or ''
+	shopping_list.items = {}
+	for i = 3, #fields, 1
+	do
+		local id, quantity, item_name = string.match(fields[i], '^(%d+):(%d+):(.*)$')
+		if id ~= nil then
+			table.insert(shopping_list.items, {id = tonumber(id), quantity = tonumber(quantity), name = item_name})
+		end
+	end
+	CraftingProfitCalculator_data:Debug('Imported ' .. #shopping_list.items .. ' items')
+	return shopping_list
+ end
+
+ -- Show the last imported shopping list with how many of each item the character already has
+ function CraftingProfitCalculator_data:show_shopping_list()
+	if CraftingProfitCalculator_shoppingDB == nil or CraftingProfitCalculator_shoppingDB.items == nil then
+		DEFAULT_CHAT_FRAME:AddMessage('CPC: no shopping list imported, use /cpci')
+		return
+	end
+	local str = CraftingProfitCalculator_shoppingDB.name
+	for _, item in ipairs(CraftingProfitCalculator_shoppingDB.items)
+	do
+		str = str .. '\n' .. item.quantity .. ' x ' .. item.name .. ' (' .. item.id .. '), have ' .. GetItemCount(item.id, true)
+	end
+	CraftingProfitCalculator_data:show(str)
+ end
+
  function CraftingProfitCalculator_data:run()
 	  CraftingProfitCalculator_data:Debug( 'Scanning bags and banks' )
 	  local inventory = {}